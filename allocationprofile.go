@@ -0,0 +1,157 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"io"
+	"time"
+)
+
+// AllocationProfile is a sampled heap profile: a call tree where every node
+// carries the live allocations that were sampled in that function.
+type AllocationProfile struct {
+	// root is the root node of the call tree.
+	root *AllocationProfileNode
+
+	// samplingInterval is the average number of bytes between samples.
+	samplingInterval uint64
+
+	// time is when the profile was collected.
+	time time.Time
+}
+
+// Allocation is a group of sampled allocations of the same size.
+type Allocation struct {
+	// Size of the sampled allocations in bytes.
+	Size uint64
+
+	// Count is the number of sampled allocations of Size bytes.
+	Count uint64
+}
+
+type AllocationProfileNode struct {
+	// The resource name for script from where the function originates.
+	scriptResourceName string
+
+	// The function name (empty string for anonymous functions.)
+	functionName string
+
+	// The id of the script from where the function originates.
+	scriptID int
+
+	// The number of the line where the function originates.
+	lineNumber int
+
+	// The number of the column where the function originates.
+	columnNumber int
+
+	// The sampled allocations made by the function itself.
+	allocations []Allocation
+
+	// The children node of this node.
+	children []*AllocationProfileNode
+
+	// The parent node of this node.
+	parent *AllocationProfileNode
+}
+
+// Returns the root node of the call tree.
+func (a *AllocationProfile) GetRoot() *AllocationProfileNode {
+	return a.root
+}
+
+// WritePprof writes the profile to w as a gzipped pprof protocol buffer, with
+// the sampled object counts and bytes as sample values.
+func (a *AllocationProfile) WritePprof(w io.Writer) error {
+	p := newPprofProfile()
+	p.addSampleType("objects", "count")
+	p.addSampleType("space", "bytes")
+	p.setPeriod("space", "bytes", int64(a.samplingInterval))
+	p.timeNanos = a.time.UnixNano()
+
+	var stack []uint64
+	var walk func(n *AllocationProfileNode)
+	walk = func(n *AllocationProfileNode) {
+		stack = append(stack, p.location(pprofFrame{
+			function: n.functionName,
+			file:     n.scriptResourceName,
+			line:     int64(n.lineNumber),
+			column:   int64(n.columnNumber),
+		}))
+		for _, alloc := range n.allocations {
+			locations := make([]uint64, len(stack))
+			for i, id := range stack {
+				locations[len(stack)-1-i] = id
+			}
+			p.addSample(locations, int64(alloc.Count), int64(alloc.Count*alloc.Size))
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+		stack = stack[:len(stack)-1]
+	}
+	// the root node is synthetic and has no allocations of its own
+	for _, child := range a.root.children {
+		walk(child)
+	}
+
+	return p.write(w)
+}
+
+// Returns function name (empty string for anonymous functions.)
+func (n *AllocationProfileNode) GetFunctionName() string {
+	return n.functionName
+}
+
+// Returns resource name for script from where the function originates.
+func (n *AllocationProfileNode) GetScriptResourceName() string {
+	return n.scriptResourceName
+}
+
+// Returns the id of the script from where the function originates.
+func (n *AllocationProfileNode) GetScriptID() int {
+	return n.scriptID
+}
+
+// Returns number of the line where the function originates.
+func (n *AllocationProfileNode) GetLineNumber() int {
+	return n.lineNumber
+}
+
+// Returns number of the column where the function originates.
+func (n *AllocationProfileNode) GetColumnNumber() int {
+	return n.columnNumber
+}
+
+// Returns the sampled allocations made by the function itself.
+func (n *AllocationProfileNode) GetAllocations() []Allocation {
+	return n.allocations
+}
+
+// Returns the sampled bytes allocated by this node and its descendants.
+func (n *AllocationProfileNode) GetTotalSize() uint64 {
+	var total uint64
+	for _, a := range n.allocations {
+		total += a.Size * a.Count
+	}
+	for _, child := range n.children {
+		total += child.GetTotalSize()
+	}
+	return total
+}
+
+// Retrieves the ancestor node, or nil if the root.
+func (n *AllocationProfileNode) GetParent() *AllocationProfileNode {
+	return n.parent
+}
+
+func (n *AllocationProfileNode) GetChildrenCount() int {
+	return len(n.children)
+}
+
+// Retrieves a child node by index.
+func (n *AllocationProfileNode) GetChild(index int) *AllocationProfileNode {
+	return n.children[index]
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestAllocationProfile_WritePprof(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	heapProfiler := v8.NewHeapProfiler(iso)
+	defer heapProfiler.Dispose()
+	heapProfiler.StartSampling(128, 0)
+	defer heapProfiler.StopSampling()
+
+	_, err := ctx.RunScript(allocationScript, "alloc.js")
+	fatalIf(t, err)
+
+	var buf bytes.Buffer
+	fatalIf(t, heapProfiler.GetAllocationProfile().WritePprof(&buf))
+
+	zr, err := gzip.NewReader(&buf)
+	fatalIf(t, err)
+	data, err := ioutil.ReadAll(zr)
+	fatalIf(t, err)
+
+	// the string table holds the sample types and the sampled functions
+	for _, s := range []string{"objects", "count", "space", "bytes", "allocate", "alloc.js"} {
+		if !bytes.Contains(data, []byte(s)) {
+			t.Errorf("expected profile to contain %q", s)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// TypedArrayKind is the element type of a typed array.
+type TypedArrayKind int
+
+const (
+	Uint8ArrayKind TypedArrayKind = iota
+	Uint8ClampedArrayKind
+	Int8ArrayKind
+	Uint16ArrayKind
+	Int16ArrayKind
+	Uint32ArrayKind
+	Int32ArrayKind
+	Float32ArrayKind
+	Float64ArrayKind
+	BigInt64ArrayKind
+	BigUint64ArrayKind
+)
+
+// ElementSize returns the size in bytes of an element of the typed array kind.
+func (k TypedArrayKind) ElementSize() int {
+	switch k {
+	case Uint8ArrayKind, Uint8ClampedArrayKind, Int8ArrayKind:
+		return 1
+	case Uint16ArrayKind, Int16ArrayKind:
+		return 2
+	case Uint32ArrayKind, Int32ArrayKind, Float32ArrayKind:
+		return 4
+	case Float64ArrayKind, BigInt64ArrayKind, BigUint64ArrayKind:
+		return 8
+	}
+	return 0
+}
+
+// backingStore is memory shared with V8, released once V8 no longer uses it.
+type backingStore struct {
+	release func()
+}
+
+var backingStoreMutex sync.Mutex
+var backingStoreRegistry = make(map[int]*backingStore)
+var backingStoreSeq = 0
+
+func registerBackingStore(release func()) int {
+	backingStoreMutex.Lock()
+	defer backingStoreMutex.Unlock()
+	backingStoreSeq++
+	ref := backingStoreSeq
+	backingStoreRegistry[ref] = &backingStore{release: release}
+	return ref
+}
+
+// copyBackingStore copies data into C memory, as V8 cannot keep Go memory past
+// a cgo call. The memory is freed, and release called if not nil, once V8 no
+// longer uses it.
+func copyBackingStore(data []byte, release func()) (unsafe.Pointer, int) {
+	var ptr unsafe.Pointer
+	if len(data) > 0 {
+		ptr = C.CBytes(data)
+	}
+	ref := registerBackingStore(func() {
+		if ptr != nil {
+			FreeCPtr(ptr)
+		}
+		if release != nil {
+			release()
+		}
+	})
+	return ptr, ref
+}
+
+//export goBackingStoreRelease
+func goBackingStoreRelease(ref int) {
+	backingStoreMutex.Lock()
+	bs := backingStoreRegistry[ref]
+	delete(backingStoreRegistry, ref)
+	backingStoreMutex.Unlock()
+	if bs != nil && bs.release != nil {
+		bs.release()
+	}
+}
+
+// NewArrayBuffer creates an ArrayBuffer with a copy of data, in memory
+// outside of the Go heap that V8 uses without copying it again: JS and Go
+// share it through ArrayBufferBytes, so Go must not modify it while JS may be
+// running in the isolate. The memory is freed once V8 no longer uses it, at
+// which point release, if not nil, is called. release may be called on any
+// goroutine. Use NewArrayBufferFromPointer to avoid the initial copy.
+func NewArrayBuffer(iso *Isolate, data []byte, release func()) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new ArrayBuffer: Isolate cannot be <nil>")
+	}
+	iso.checkThread()
+	ptr, ref := copyBackingStore(data, release)
+	return NewValueStruct(C.NewArrayBuffer(iso.ptr, ptr, C.size_t(len(data)), C.int(ref)), iso), nil
+}
+
+// NewArrayBufferFromPointer creates an ArrayBuffer backed by length bytes of
+// memory at ptr that is not managed by Go, such as memory from C.malloc or a
+// memory-mapped file. The memory must stay valid until release is called,
+// once V8 no longer uses it; release may be called on any goroutine.
+func NewArrayBufferFromPointer(iso *Isolate, ptr unsafe.Pointer, length int, release func()) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new ArrayBuffer: Isolate cannot be <nil>")
+	}
+	if ptr == nil && length > 0 {
+		return nil, errors.New("v8go: failed to create new ArrayBuffer: pointer cannot be <nil>")
+	}
+	if length < 0 {
+		return nil, errors.New("v8go: failed to create new ArrayBuffer: length cannot be negative")
+	}
+	iso.checkThread()
+	ref := registerBackingStore(release)
+	return NewValueStruct(C.NewArrayBuffer(iso.ptr, ptr, C.size_t(length), C.int(ref)), iso), nil
+}
+
+// NewTypedArray creates a typed array of the given kind viewing length
+// elements of buffer, which must be an ArrayBuffer, from byteOffset.
+// byteOffset must be a multiple of the element size.
+func NewTypedArray(kind TypedArrayKind, buffer *Value, byteOffset, length int) (*Value, error) {
+	size := kind.ElementSize()
+	if size == 0 {
+		return nil, fmt.Errorf("v8go: unknown typed array kind %d", kind)
+	}
+	if buffer == nil {
+		return nil, errors.New("v8go: typed arrays can only be created over an ArrayBuffer")
+	}
+	buffer.ISO.checkThread()
+	if !buffer.IsArrayBuffer() {
+		return nil, errors.New("v8go: typed arrays can only be created over an ArrayBuffer")
+	}
+	if byteOffset < 0 || length < 0 {
+		return nil, errors.New("v8go: typed array offset and length cannot be negative")
+	}
+	if byteOffset%size != 0 {
+		return nil, fmt.Errorf("v8go: typed array offset %d is not a multiple of the element size %d", byteOffset, size)
+	}
+	contents := C.ValueArrayBufferContents(buffer.ptr)
+	if uint64(byteOffset)+uint64(length)*uint64(size) > uint64(contents.byteLength) {
+		return nil, fmt.Errorf("v8go: typed array of %d elements at offset %d is out of the bounds of the ArrayBuffer", length, byteOffset)
+	}
+	rtn := C.NewTypedArray(buffer.ptr, C.int(kind), C.size_t(byteOffset), C.size_t(length))
+	return valueResult(buffer.ISO, rtn)
+}
+
+// NewUint8Array creates a Uint8Array over length bytes of buffer from byteOffset.
+func NewUint8Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Uint8ArrayKind, buffer, byteOffset, length)
+}
+
+// NewUint8ClampedArray creates a Uint8ClampedArray over length bytes of buffer from byteOffset.
+func NewUint8ClampedArray(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Uint8ClampedArrayKind, buffer, byteOffset, length)
+}
+
+// NewInt8Array creates an Int8Array over length bytes of buffer from byteOffset.
+func NewInt8Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Int8ArrayKind, buffer, byteOffset, length)
+}
+
+// NewUint16Array creates a Uint16Array over length elements of buffer from byteOffset.
+func NewUint16Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Uint16ArrayKind, buffer, byteOffset, length)
+}
+
+// NewInt16Array creates an Int16Array over length elements of buffer from byteOffset.
+func NewInt16Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Int16ArrayKind, buffer, byteOffset, length)
+}
+
+// NewUint32Array creates a Uint32Array over length elements of buffer from byteOffset.
+func NewUint32Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Uint32ArrayKind, buffer, byteOffset, length)
+}
+
+// NewInt32Array creates an Int32Array over length elements of buffer from byteOffset.
+func NewInt32Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Int32ArrayKind, buffer, byteOffset, length)
+}
+
+// NewFloat32Array creates a Float32Array over length elements of buffer from byteOffset.
+func NewFloat32Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Float32ArrayKind, buffer, byteOffset, length)
+}
+
+// NewFloat64Array creates a Float64Array over length elements of buffer from byteOffset.
+func NewFloat64Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(Float64ArrayKind, buffer, byteOffset, length)
+}
+
+// NewBigInt64Array creates a BigInt64Array over length elements of buffer from byteOffset.
+func NewBigInt64Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(BigInt64ArrayKind, buffer, byteOffset, length)
+}
+
+// NewBigUint64Array creates a BigUint64Array over length elements of buffer from byteOffset.
+func NewBigUint64Array(buffer *Value, byteOffset, length int) (*Value, error) {
+	return NewTypedArray(BigUint64ArrayKind, buffer, byteOffset, length)
+}
+
+// ArrayBufferBytes returns the memory of an ArrayBuffer, or the bytes viewed by
+// a typed array or DataView, without copying it; it returns nil for any other
+// value. The slice aliases memory owned by V8: it must only be used from the
+// goroutine that owns the isolate, while v is alive and the buffer is not
+// detached. Use GetCopiedArrayBufferViewContents for a copy. Buffers larger
+// than 1 GiB are not supported.
+func (v *Value) ArrayBufferBytes() []byte {
+	contents := C.ValueArrayBufferContents(v.ptr)
+	if contents.data == nil {
+		if v.IsArrayBuffer() || v.IsArrayBufferView() {
+			return []byte{}
+		}
+		return nil
+	}
+	n := int(contents.byteLength)
+	return (*[1 << 30]byte)(contents.data)[:n:n]
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewArrayBuffer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+
+	data := []byte{1, 2, 3, 4}
+	released := make(chan struct{}, 1)
+	buf, err := v8.NewArrayBuffer(iso, data, func() { released <- struct{}{} })
+	fatalIf(t, err)
+	if !buf.IsArrayBuffer() {
+		t.Fatal("expected ArrayBuffer")
+	}
+	fatalIf(t, ctx.Global().Set("buf", buf))
+
+	b := buf.ArrayBufferBytes()
+	if string(b) != string(data) {
+		t.Errorf("expected a copy of the data, got %v", b)
+	}
+
+	// writes from JS are visible in Go without copying
+	_, err = ctx.RunScript("new Uint8Array(buf)[0] = 42", "write.js")
+	fatalIf(t, err)
+	if b[0] != 42 {
+		t.Errorf("expected JS write to be visible in Go, got %d", b[0])
+	}
+	if data[0] != 1 {
+		t.Errorf("expected the Go slice to be copied, got %d", data[0])
+	}
+
+	// and writes from Go are visible in JS
+	b[3] = 7
+	val, err := ctx.RunScript("new Uint8Array(buf)[3]", "read.js")
+	fatalIf(t, err)
+	if val.Int32() != 7 {
+		t.Errorf("expected Go write to be visible in JS, got %d", val.Int32())
+	}
+
+	ctx.Close()
+	iso.Dispose()
+	select {
+	case <-released:
+	default:
+		t.Error("expected release to be called once the isolate is disposed")
+	}
+}
+
+func TestNewTypedArray(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	data := make([]byte, 32)
+	binary.LittleEndian.PutUint64(data[8:], math.Float64bits(1.5))
+	buf, err := v8.NewArrayBuffer(iso, data, nil)
+	fatalIf(t, err)
+
+	f64, err := v8.NewFloat64Array(buf, 8, 2)
+	fatalIf(t, err)
+	if !f64.IsFloat64Array() {
+		t.Fatal("expected Float64Array")
+	}
+	fatalIf(t, ctx.Global().Set("f64", f64))
+	val, err := ctx.RunScript("f64[1] = 2.5; f64.length + ':' + f64[0]", "f64.js")
+	fatalIf(t, err)
+	if val.String() != "2:1.5" {
+		t.Errorf("expected 2:1.5, got %s", val.String())
+	}
+	bufBytes := buf.ArrayBufferBytes()
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(bufBytes[16:])); got != 2.5 {
+		t.Errorf("expected 2.5 in the backing store, got %v", got)
+	}
+
+	if b := f64.ArrayBufferBytes(); len(b) != 16 || &b[0] != &bufBytes[8] {
+		t.Error("expected ArrayBufferBytes to return the viewed range")
+	}
+
+	constructors := map[string]func(*v8.Value, int, int) (*v8.Value, error){
+		"Uint8Array":        v8.NewUint8Array,
+		"Uint8ClampedArray": v8.NewUint8ClampedArray,
+		"Int8Array":         v8.NewInt8Array,
+		"Uint16Array":       v8.NewUint16Array,
+		"Int16Array":        v8.NewInt16Array,
+		"Uint32Array":       v8.NewUint32Array,
+		"Int32Array":        v8.NewInt32Array,
+		"Float32Array":      v8.NewFloat32Array,
+		"BigInt64Array":     v8.NewBigInt64Array,
+		"BigUint64Array":    v8.NewBigUint64Array,
+	}
+	for name, fn := range constructors {
+		arr, err := fn(buf, 0, 2)
+		fatalIf(t, err)
+		fatalIf(t, ctx.Global().Set("arr", arr))
+		val, err := ctx.RunScript("arr.constructor.name", "name.js")
+		fatalIf(t, err)
+		if val.String() != name {
+			t.Errorf("expected %s, got %s", name, val.String())
+		}
+	}
+
+	if _, err := v8.NewUint32Array(buf, 2, 1); err == nil {
+		t.Error("expected error for a misaligned offset")
+	}
+	if _, err := v8.NewFloat64Array(buf, 8, 4); err == nil {
+		t.Error("expected error for an out of bounds length")
+	}
+	if _, err := v8.NewUint8Array(f64, 0, 1); err == nil {
+		t.Error("expected error for a non ArrayBuffer")
+	}
+}
+
+func TestValueArrayBufferBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("new Uint16Array([1, 2, 3]).subarray(1)", "view.js")
+	fatalIf(t, err)
+	b := val.ArrayBufferBytes()
+	if len(b) != 4 || binary.LittleEndian.Uint16(b) != 2 {
+		t.Errorf("expected the viewed bytes, got %v", b)
+	}
+
+	val, err = ctx.RunScript("new ArrayBuffer(0)", "empty.js")
+	fatalIf(t, err)
+	if b := val.ArrayBufferBytes(); b == nil || len(b) != 0 {
+		t.Errorf("expected empty slice, got %v", b)
+	}
+
+	val, err = ctx.RunScript("'not a buffer'", "string.js")
+	fatalIf(t, err)
+	if b := val.ArrayBufferBytes(); b != nil {
+		t.Errorf("expected nil, got %v", b)
+	}
+}
+
+func TestNewArrayBufferFromPointer(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if _, err := v8.NewArrayBufferFromPointer(iso, nil, 8, nil); err == nil {
+		t.Error("expected error for a nil pointer")
+	}
+	buf, err := v8.NewArrayBufferFromPointer(iso, nil, 0, nil)
+	fatalIf(t, err)
+	if !buf.IsArrayBuffer() {
+		t.Error("expected ArrayBuffer")
+	}
+}
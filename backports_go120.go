@@ -0,0 +1,11 @@
+//go:build go1.20
+// +build go1.20
+
+package v8go
+
+import "context"
+
+// contextCause returns context.Cause(ctx), available since go 1.20.
+func contextCause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
@@ -0,0 +1,12 @@
+//go:build !go1.20
+// +build !go1.20
+
+package v8go
+
+import "context"
+
+// contextCause backports context.Cause from go 1.20: contexts have no cause
+// before it, so it returns ctx.Err().
+func contextCause(ctx context.Context) error {
+	return ctx.Err()
+}
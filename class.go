@@ -0,0 +1,368 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Class is a JS class bound to a Go struct type, created with NewClass.
+// Each instance created in JS with `new` wraps a new Go value, and the
+// exported methods of the Go type are methods of the class prototype.
+type Class struct {
+	iso      *Isolate
+	name     string
+	typ      reflect.Type // pointer to the struct type of the receivers
+	ctor     reflect.Value
+	tmpl     *FunctionTemplate
+	instance *ObjectTemplate
+
+	// wrappers holds the weak refs of the JS objects wrapping each receiver,
+	// so that returning a receiver from a method returns the same object
+	wrappersMutex sync.Mutex
+	wrappers      map[classWrapperKey]int
+}
+
+// classWrapperKey identifies the JS object of a receiver within a context.
+type classWrapperKey struct {
+	ctx  *Context
+	recv uintptr
+}
+
+// classHandle is a Go receiver wrapped by a JS object.
+type classHandle struct {
+	class *Class
+	recv  reflect.Value
+}
+
+var classHandleMutex sync.Mutex
+var classHandles = make(map[int]*classHandle)
+var classHandleSeq = 0
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// NewClass creates a JS class for a named Go struct type. typeOrConstructor is
+// either the reflect.Type of the struct or of a pointer to it, in which case
+// `new` creates a zero value, or a constructor function returning a pointer
+// to the struct and optionally an error, whose arguments are converted from
+// the JS arguments with FromJS.
+//
+// The exported methods of the pointer type are added to the prototype of the
+// class, under their Go names. Their arguments are converted with FromJS,
+// missing arguments being zero values, and their results with ToJS: a method
+// returning nothing returns undefined, several results are returned as an
+// Array, and a non-nil trailing error is thrown as a JS Error. Receivers of
+// the class itself are passed and returned as instances of the class.
+//
+// The Go receiver is kept alive until its JS object is garbage collected or
+// the isolate is disposed.
+func NewClass(iso *Isolate, typeOrConstructor interface{}) (*Class, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new Class: Isolate cannot be <nil>")
+	}
+	c := &Class{iso: iso, wrappers: make(map[classWrapperKey]int)}
+	switch v := typeOrConstructor.(type) {
+	case reflect.Type:
+		c.typ = v
+		if c.typ.Kind() != reflect.Ptr {
+			c.typ = reflect.PtrTo(c.typ)
+		}
+	default:
+		fn := reflect.ValueOf(v)
+		if fn.Kind() != reflect.Func || fn.IsNil() {
+			return nil, fmt.Errorf("v8go: class must be a reflect.Type or a constructor function, got %T", v)
+		}
+		ft := fn.Type()
+		if ft.NumOut() < 1 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+			return nil, fmt.Errorf("v8go: class constructor must return a struct pointer and optionally an error, got %s", ft)
+		}
+		c.typ = ft.Out(0)
+		c.ctor = fn
+	}
+	if c.typ.Kind() != reflect.Ptr || c.typ.Elem().Kind() != reflect.Struct || c.typ.Elem().Name() == "" {
+		return nil, fmt.Errorf("v8go: class type must be a named struct, got %s", c.typ)
+	}
+	c.name = c.typ.Elem().Name()
+
+	c.tmpl = NewFunctionTemplateWithOptions(iso, c.construct, FunctionTemplateOptions{
+		Name:     c.name,
+		Length:   c.numIn(c.ctor),
+		Behavior: ConstructorOnly,
+	})
+	c.tmpl.ReadOnlyPrototype()
+	c.instance = c.tmpl.InstanceTemplate()
+	c.instance.SetInternalFieldCount(1)
+	proto := c.tmpl.PrototypeTemplate()
+	for i := 0; i < c.typ.NumMethod(); i++ {
+		m := c.typ.Method(i)
+		method := NewFunctionTemplateWithOptions(iso, c.method(m), FunctionTemplateOptions{
+			Name:     m.Name,
+			Length:   c.numIn(m.Func) - 1,
+			Behavior: ConstructorThrow,
+		})
+		if err := proto.Set(m.Name, method, DontEnum); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Name returns the name of the class, which is the name of the Go type.
+func (c *Class) Name() string {
+	return c.name
+}
+
+// FunctionTemplate returns the template of the class constructor, which can
+// be set on an ObjectTemplate, such as the global template of a context.
+func (c *Class) FunctionTemplate() *FunctionTemplate {
+	return c.tmpl
+}
+
+// GetFunction returns the class constructor in the given context.
+func (c *Class) GetFunction(ctx *Context) *Function {
+	return c.tmpl.GetFunction(ctx)
+}
+
+// NewInstance creates an instance of the class that wraps receiver, an
+// existing Go value of the class type, without calling the constructor.
+func (c *Class) NewInstance(ctx *Context, receiver interface{}) (*Object, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context cannot be <nil>")
+	}
+	recv := reflect.ValueOf(receiver)
+	if !recv.IsValid() || recv.Type() != c.typ || recv.IsNil() {
+		return nil, fmt.Errorf("v8go: receiver of class %s must be a non-nil %s, got %T", c.name, c.typ, receiver)
+	}
+	obj, err := c.instance.NewInstance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.wrap(ctx, obj, recv); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Receiver returns the Go value wrapped by an instance of the class.
+func (c *Class) Receiver(obj *Object) (interface{}, error) {
+	recv, err := c.receiver(obj)
+	if err != nil {
+		return nil, err
+	}
+	return recv.Interface(), nil
+}
+
+func (c *Class) wrap(ctx *Context, obj *Object, recv reflect.Value) error {
+	classHandleMutex.Lock()
+	classHandleSeq++
+	handle := classHandleSeq
+	classHandles[handle] = &classHandle{class: c, recv: recv}
+	classHandleMutex.Unlock()
+
+	if err := obj.SetInternalField(0, float64(handle)); err != nil {
+		releaseClassHandle(handle)
+		return err
+	}
+	key := classWrapperKey{ctx: ctx, recv: recv.Pointer()}
+	var ref int
+	ref = c.iso.setWeak(obj.Value, func() {
+		releaseClassHandle(handle)
+		c.wrappersMutex.Lock()
+		defer c.wrappersMutex.Unlock()
+		// the receiver may have been wrapped again since
+		if c.wrappers[key] == ref {
+			delete(c.wrappers, key)
+		}
+	})
+	c.wrappersMutex.Lock()
+	c.wrappers[key] = ref
+	c.wrappersMutex.Unlock()
+	return nil
+}
+
+// wrapper returns the JS object wrapping recv in ctx, or nil if there is none.
+func (c *Class) wrapper(ctx *Context, recv reflect.Value) *Object {
+	c.wrappersMutex.Lock()
+	ref, ok := c.wrappers[classWrapperKey{ctx: ctx, recv: recv.Pointer()}]
+	c.wrappersMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return getWeak(ctx, ref)
+}
+
+func releaseClassHandle(handle int) {
+	classHandleMutex.Lock()
+	defer classHandleMutex.Unlock()
+	delete(classHandles, handle)
+}
+
+func (c *Class) receiver(obj *Object) (reflect.Value, error) {
+	if obj == nil || obj.InternalFieldCount() < 1 {
+		return reflect.Value{}, fmt.Errorf("v8go: object is not an instance of class %s", c.name)
+	}
+	field := obj.GetInternalField(0)
+	if !field.IsNumber() {
+		return reflect.Value{}, fmt.Errorf("v8go: object is not an instance of class %s", c.name)
+	}
+	classHandleMutex.Lock()
+	h := classHandles[int(field.Integer())]
+	classHandleMutex.Unlock()
+	if h == nil || h.class != c {
+		return reflect.Value{}, fmt.Errorf("v8go: object is not an instance of class %s", c.name)
+	}
+	return h.recv, nil
+}
+
+func (c *Class) construct(info *FunctionCallbackInfo) *Value {
+	ctx := info.Context()
+	this := info.This()
+	if this.InternalFieldCount() < 1 || !this.GetInternalField(0).IsUndefined() {
+		return c.throw(errorKindTypeError, "Illegal constructor")
+	}
+
+	recv := reflect.New(c.typ.Elem())
+	if c.ctor.IsValid() {
+		out, err := c.call(ctx, c.ctor, info.Args())
+		if err != nil {
+			return c.iso.throwError(err)
+		}
+		recv = out[0]
+		if recv.IsNil() {
+			return c.throw(errorKindError, fmt.Sprintf("constructor of class %s returned nil", c.name))
+		}
+	}
+	if err := c.wrap(ctx, this, recv); err != nil {
+		return c.iso.throwError(err)
+	}
+	return nil
+}
+
+func (c *Class) method(m reflect.Method) FunctionCallback {
+	return func(info *FunctionCallbackInfo) *Value {
+		ctx := info.Context()
+		recv, err := c.receiver(info.This())
+		if err != nil {
+			return c.throw(errorKindTypeError, "Illegal invocation")
+		}
+		out, err := c.call(ctx, recv.Method(m.Index), info.Args())
+		if err != nil {
+			return c.iso.throwError(err)
+		}
+		var val *Value
+		switch len(out) {
+		case 0:
+			return nil
+		case 1:
+			val, err = c.resultToJS(ctx, out[0])
+		default:
+			results := make([]interface{}, len(out))
+			for i, r := range out {
+				v, err := c.resultToJS(ctx, r)
+				if err != nil {
+					return c.iso.throwError(err)
+				}
+				results[i] = v
+			}
+			val, err = ToJS(ctx, results)
+		}
+		if err != nil {
+			return c.iso.throwError(err)
+		}
+		return val
+	}
+}
+
+// numIn returns the number of arguments expected by fn, not counting variadic
+// arguments, or 0 for an invalid fn.
+func (c *Class) numIn(fn reflect.Value) int {
+	if !fn.IsValid() {
+		return 0
+	}
+	n := fn.Type().NumIn()
+	if fn.Type().IsVariadic() {
+		n--
+	}
+	return n
+}
+
+// call calls fn with the JS arguments, and returns its results without the
+// trailing error, if any.
+func (c *Class) call(ctx *Context, fn reflect.Value, args []*Value) ([]reflect.Value, error) {
+	ft := fn.Type()
+	n := ft.NumIn()
+	if ft.IsVariadic() {
+		// the variadic arguments are the JS arguments past the fixed ones
+		n--
+		if len(args) > n {
+			n = len(args)
+		}
+	}
+	in := make([]reflect.Value, n)
+	for i := range in {
+		var t reflect.Type
+		if ft.IsVariadic() && i >= ft.NumIn()-1 {
+			t = ft.In(ft.NumIn() - 1).Elem()
+		} else {
+			t = ft.In(i)
+		}
+		arg := reflect.New(t).Elem()
+		if i < len(args) {
+			if err := c.argFromJS(args[i], arg); err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i, err)
+			}
+		}
+		in[i] = arg
+	}
+	return c.results(fn.Call(in))
+}
+
+func (c *Class) results(out []reflect.Value) ([]reflect.Value, error) {
+	if len(out) > 0 && out[len(out)-1].Type() == errorType {
+		if err := out[len(out)-1]; !err.IsNil() {
+			return nil, err.Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+	return out, nil
+}
+
+func (c *Class) argFromJS(val *Value, arg reflect.Value) error {
+	if arg.Type() == c.typ && !val.IsNullOrUndefined() {
+		obj, err := val.AsObject()
+		if err != nil {
+			return err
+		}
+		recv, err := c.receiver(obj)
+		if err != nil {
+			return err
+		}
+		arg.Set(recv)
+		return nil
+	}
+	return FromJS(val, arg.Addr().Interface())
+}
+
+func (c *Class) resultToJS(ctx *Context, result reflect.Value) (*Value, error) {
+	if result.Type() == c.typ && !result.IsNil() {
+		// a receiver that is already wrapped, such as the one of the method, keeps its object
+		if obj := c.wrapper(ctx, result); obj != nil {
+			return obj.Value, nil
+		}
+		obj, err := c.NewInstance(ctx, result.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return obj.Value, nil
+	}
+	return ToJS(ctx, result.Interface())
+}
+
+func (c *Class) throw(kind errorKind, msg string) *Value {
+	return c.iso.ThrowException(newErrorValue(c.iso, kind, msg))
+}
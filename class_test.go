@@ -0,0 +1,172 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+type classPlayer struct {
+	Name string
+	X, Y int32
+}
+
+func (p *classPlayer) Move(dx, dy int32) {
+	p.X += dx
+	p.Y += dy
+}
+
+func (p *classPlayer) Position() string {
+	return fmt.Sprintf("%s@%d,%d", p.Name, p.X, p.Y)
+}
+
+func (p *classPlayer) Teleport(to string) error {
+	if to == "" {
+		return errors.New("nowhere to go")
+	}
+	p.Name = to
+	return nil
+}
+
+func (p *classPlayer) Self() *classPlayer {
+	return p
+}
+
+func (p *classPlayer) Clone() *classPlayer {
+	c := *p
+	return &c
+}
+
+func (p *classPlayer) Sum(values ...int32) int32 {
+	var sum int32
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func newClassPlayer(name string) (*classPlayer, error) {
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	return &classPlayer{Name: name}, nil
+}
+
+func TestNewClass(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	class, err := v8.NewClass(iso, newClassPlayer)
+	fatalIf(t, err)
+	if class.Name() != "classPlayer" {
+		t.Errorf("expected classPlayer, got %s", class.Name())
+	}
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("Player", class.FunctionTemplate()))
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source   string
+		expected string
+	}{
+		{"const p = new Player('bob'); p.Move(1, 2); p.Move(3); p.Position()", "bob@4,2"},
+		{"p instanceof Player", "true"},
+		{"p.constructor.name", "classPlayer"},
+		{"Object.keys(p).length", "0"},
+		{"const c = p.Clone(); c.Move(1, 1); c.Position() + ' ' + p.Position()", "bob@5,3 bob@4,2"},
+		{"c instanceof Player", "true"},
+		{"p.Self() === p && p.Self().Self() === p && c.Self() === c", "true"},
+		{"p.Sum() + p.Sum(1, 2, 3)", "6"},
+		{"try { p.Teleport('') } catch (e) { e.message }", "nowhere to go"},
+		{"try { new Player('') } catch (e) { e.message }", "name is required"},
+		{"try { Player('x') } catch (e) { e instanceof TypeError }", "true"},
+		{"try { p.Move.call({}) } catch (e) { e.message }", "Illegal invocation"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "class.js")
+		fatalIf(t, err)
+		if val.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.expected, val.String())
+		}
+	}
+
+	val, err := ctx.RunScript("p", "p.js")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+	recv, err := class.Receiver(obj)
+	fatalIf(t, err)
+	if p := recv.(*classPlayer); p.X != 4 || p.Y != 2 {
+		t.Errorf("expected the Go receiver to be updated, got %+v", p)
+	}
+	if _, err := class.Receiver(ctx.Global()); err == nil {
+		t.Error("expected error for an object that is not an instance")
+	}
+}
+
+func TestClassNewInstance(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	class, err := v8.NewClass(iso, reflect.TypeOf(classPlayer{}))
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("Player", class.GetFunction(ctx)))
+
+	player := &classPlayer{Name: "go"}
+	obj, err := class.NewInstance(ctx, player)
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("p", obj))
+	val, err := ctx.RunScript("p.Teleport('js'); p instanceof Player", "instance.js")
+	fatalIf(t, err)
+	if !val.Boolean() || player.Name != "js" {
+		t.Errorf("expected the wrapped Go value to be used, got %+v", player)
+	}
+
+	val, err = ctx.RunScript("new Player().Position()", "zero.js")
+	fatalIf(t, err)
+	if val.String() != "@0,0" {
+		t.Errorf("expected a zero value receiver, got %s", val.String())
+	}
+
+	if _, err := class.NewInstance(ctx, classPlayer{}); err == nil {
+		t.Error("expected error for a receiver of the wrong type")
+	}
+}
+
+func TestNewClass_Errors(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	tests := []interface{}{
+		nil,
+		42,
+		reflect.TypeOf(0),
+		reflect.TypeOf(struct{}{}),
+		func() classPlayer { return classPlayer{} },
+		func() (*classPlayer, int) { return nil, 0 },
+	}
+	for _, tt := range tests {
+		if _, err := v8.NewClass(iso, tt); err == nil {
+			t.Errorf("%T: expected error", tt)
+		}
+	}
+	if _, err := v8.NewClass(nil, reflect.TypeOf(classPlayer{})); err == nil || !strings.Contains(err.Error(), "Isolate") {
+		t.Errorf("expected error for a nil Isolate, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ package v8go
 // #include "v8go.h"
 import "C"
 import (
+	"context"
 	"sync"
 	"unsafe"
 )
@@ -92,6 +93,7 @@ func (c *Context) Isolate() *Isolate {
 // reference for the script and used in the stack trace if there is an error.
 // error will be of type `JSError` if not nil.
 func (c *Context) RunScript(source string, origin string) (*Value, error) {
+	c.iso.checkThread()
 	cSource := C.CString(source)
 	cOrigin := C.CString(origin)
 	defer FreeCPtr(unsafe.Pointer(cSource))
@@ -101,6 +103,15 @@ func (c *Context) RunScript(source string, origin string) (*Value, error) {
 	return valueResult(c.iso, rtn)
 }
 
+// RunScriptContext executes the source JavaScript like RunScript, but terminates the
+// execution when ctx is done. The error is ErrDeadlineExceeded if the deadline of ctx
+// passed, ErrExecutionTerminated if ctx was canceled and `JSError` otherwise.
+func (c *Context) RunScriptContext(ctx context.Context, source string, origin string) (*Value, error) {
+	return c.iso.runContext(ctx, func() (*Value, error) {
+		return c.RunScript(source, origin)
+	})
+}
+
 // Global returns the GlobalObject proxy object.
 // Global proxy object is a thin wrapper whose prototype points to actual
 // context's GlobalObject object with the properties like Object, etc. This is
@@ -115,6 +126,7 @@ func (c *Context) Global() *Object {
 // PerformMicrotaskCheckpoint runs the default MicrotaskQueue until empty.
 // This is used to make progress on Promises.
 func (c *Context) PerformMicrotaskCheckpoint() {
+	c.iso.checkThread()
 	C.IsolatePerformMicrotaskCheckpoint(c.iso.ptr)
 }
 
@@ -168,14 +180,14 @@ func goContext(ref int) C.ContextPtr {
 
 func valueResult(ctx *Isolate, rtn C.RtnValue) (*Value, error) {
 	if rtn.value == nil {
-		return nil, newJSError(rtn.error)
+		return nil, ctx.executionError(newJSError(ctx, rtn.error))
 	}
 	return NewValueStruct(rtn.value, ctx), nil
 }
 
 func objectResult(ctx *Isolate, rtn C.RtnValue) (*Object, error) {
 	if rtn.value == nil {
-		return nil, newJSError(rtn.error)
+		return nil, ctx.executionError(newJSError(ctx, rtn.error))
 	}
 	return &Object{NewValueStruct(rtn.value, ctx)}, nil
 }
@@ -0,0 +1,46 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package v8go_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestContextRunScriptContext_Cause(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	cause := errors.New("client went away")
+	canceled, cancel := context.WithCancelCause(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel(cause)
+	}()
+	_, err := ctx.RunScriptContext(canceled, "while (true) {}", "forever.js")
+	if !errors.Is(err, v8.ErrExecutionTerminated) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected ErrExecutionTerminated, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("expected error to wrap the cause, got %v", err)
+	}
+
+	deadline, cancelDeadline := context.WithDeadlineCause(context.Background(), time.Now().Add(50*time.Millisecond), cause)
+	defer cancelDeadline()
+	_, err = ctx.RunScriptContext(deadline, "while (true) {}", "forever.js")
+	if !errors.Is(err, v8.ErrDeadlineExceeded) || !errors.Is(err, cause) {
+		t.Errorf("expected ErrDeadlineExceeded with the cause, got %v", err)
+	}
+}
@@ -5,9 +5,12 @@
 package v8go_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -96,6 +99,60 @@ func TestContextRegistry(t *testing.T) {
 	}
 }
 
+func TestContextRunScriptContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ctx.RunScriptContext(deadline, "while (true) {}", "forever.js")
+	if !errors.Is(err, v8.ErrDeadlineExceeded) {
+		t.Fatalf("expected ErrDeadlineExceeded, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err = ctx.RunScriptContext(canceled, "while (true) {}", "forever.js")
+	if !errors.Is(err, v8.ErrExecutionTerminated) {
+		t.Fatalf("expected ErrExecutionTerminated, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to wrap context.Canceled, got %v", err)
+	}
+
+	// the isolate is resumed after termination
+	val, err := ctx.RunScriptContext(context.Background(), "1 + 1", "resume.js")
+	fatalIf(t, err)
+	if val.Int32() != 2 {
+		t.Errorf("expected 2, got %v", val)
+	}
+
+	// JS exceptions are still reported as JSError
+	_, err = ctx.RunScriptContext(context.Background(), "throw new Error('oops')", "throw.js")
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Errorf("expected JSError, got %T", err)
+	}
+
+	// a done context does not run the script at all
+	_, err = ctx.RunScriptContext(canceled, "globalThis.ran = true", "skipped.js")
+	if !errors.Is(err, v8.ErrExecutionTerminated) {
+		t.Fatalf("expected ErrExecutionTerminated, got %v", err)
+	}
+	if ran, _ := ctx.Global().Get("ran"); !ran.IsUndefined() {
+		t.Error("expected script not to run with a done context")
+	}
+}
+
 func TestMemoryLeak(t *testing.T) {
 	t.Parallel()
 
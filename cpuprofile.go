@@ -8,7 +8,12 @@ package v8go
 #include "v8go.h"
 */
 import "C"
-import "time"
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
 
 type CPUProfile struct {
 	p *C.CPUProfile
@@ -19,6 +24,16 @@ type CPUProfile struct {
 	// root is the root node of the top down call tree.
 	root *CPUProfileNode
 
+	// nodes are the nodes of the call tree by id.
+	nodes map[int]*CPUProfileNode
+
+	// samples are the ids of the nodes on top of the stack for each sample.
+	samples []int
+
+	// timestamps are the times the samples were taken, relative to the
+	// same starting point as startTimeOffset.
+	timestamps []time.Duration
+
 	// startTimeOffset is the time when the profile recording was started
 	// since some unspecified starting point.
 	startTimeOffset time.Duration
@@ -44,6 +59,184 @@ func (c *CPUProfile) GetDuration() time.Duration {
 	return c.endTimeOffset - c.startTimeOffset
 }
 
+// Returns the number of samples recorded.
+func (c *CPUProfile) GetSamplesCount() int {
+	return len(c.samples)
+}
+
+// Returns the node that was on top of the stack for the sample at index.
+func (c *CPUProfile) GetSample(index int) *CPUProfileNode {
+	return c.nodes[c.samples[index]]
+}
+
+// Returns the time the sample at index was taken, as an offset from the
+// profile start.
+func (c *CPUProfile) GetSampleTimestamp(index int) time.Duration {
+	return c.timestamps[index] - c.startTimeOffset
+}
+
+// WritePprof writes the profile to w as a gzipped pprof protocol buffer, with
+// the number of samples and the sampled CPU time as sample values.
+func (c *CPUProfile) WritePprof(w io.Writer) error {
+	type nodeTime struct {
+		count int64
+		nanos int64
+	}
+	times := make(map[*CPUProfileNode]*nodeTime)
+	var total int64
+	if len(c.samples) > 0 {
+		prev := c.startTimeOffset
+		for i, id := range c.samples {
+			node := c.nodes[id]
+			if node == nil {
+				continue
+			}
+			t := times[node]
+			if t == nil {
+				t = &nodeTime{}
+				times[node] = t
+			}
+			t.count++
+			t.nanos += int64(c.timestamps[i] - prev)
+			prev = c.timestamps[i]
+			total++
+		}
+	} else {
+		// samples were not recorded, so spread the duration over hit counts
+		var walk func(n *CPUProfileNode)
+		walk = func(n *CPUProfileNode) {
+			if n.hitCount > 0 {
+				times[n] = &nodeTime{count: int64(n.hitCount)}
+				total += int64(n.hitCount)
+			}
+			for _, child := range n.children {
+				walk(child)
+			}
+		}
+		walk(c.root)
+		if total > 0 {
+			interval := int64(c.GetDuration()) / total
+			for _, t := range times {
+				t.nanos = t.count * interval
+			}
+		}
+	}
+
+	p := newPprofProfile()
+	p.addSampleType("samples", "count")
+	p.addSampleType("cpu", "nanoseconds")
+	if total > 0 {
+		p.setPeriod("cpu", "nanoseconds", int64(c.GetDuration())/total)
+	} else {
+		p.setPeriod("cpu", "nanoseconds", 0)
+	}
+	p.durationNanos = int64(c.GetDuration())
+
+	var stack []uint64
+	var walk func(n *CPUProfileNode)
+	walk = func(n *CPUProfileNode) {
+		stack = append(stack, p.location(pprofFrame{
+			function: n.functionName,
+			file:     n.scriptResourceName,
+			line:     int64(n.lineNumber),
+			column:   int64(n.columnNumber),
+		}))
+		if t := times[n]; t != nil {
+			locations := make([]uint64, len(stack))
+			for i, id := range stack {
+				locations[len(stack)-1-i] = id
+			}
+			p.addSample(locations, t.count, t.nanos)
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+		stack = stack[:len(stack)-1]
+	}
+	walk(c.root)
+
+	return p.write(w)
+}
+
+type chromeCPUProfile struct {
+	Nodes      []chromeCPUProfileNode `json:"nodes"`
+	StartTime  int64                  `json:"startTime"`
+	EndTime    int64                  `json:"endTime"`
+	Samples    []int                  `json:"samples"`
+	TimeDeltas []int64                `json:"timeDeltas"`
+}
+
+type chromeCPUProfileNode struct {
+	ID            int                   `json:"id"`
+	CallFrame     chromeCallFrame       `json:"callFrame"`
+	HitCount      int                   `json:"hitCount"`
+	Children      []int                 `json:"children,omitempty"`
+	DeoptReason   string                `json:"deoptReason,omitempty"`
+	PositionTicks []chromePositionTicks `json:"positionTicks,omitempty"`
+}
+
+type chromeCallFrame struct {
+	FunctionName string `json:"functionName"`
+	ScriptID     string `json:"scriptId"`
+	URL          string `json:"url"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+type chromePositionTicks struct {
+	Line  int `json:"line"`
+	Ticks int `json:"ticks"`
+}
+
+// WriteChromeJSON writes the profile to w in the JSON format of the DevTools
+// Profiler.Profile type, as saved in .cpuprofile files.
+func (c *CPUProfile) WriteChromeJSON(w io.Writer) error {
+	profile := chromeCPUProfile{
+		StartTime:  c.startTimeOffset.Microseconds(),
+		EndTime:    c.endTimeOffset.Microseconds(),
+		Samples:    c.samples,
+		TimeDeltas: make([]int64, len(c.timestamps)),
+	}
+	if profile.Samples == nil {
+		profile.Samples = []int{}
+	}
+	prev := c.startTimeOffset
+	for i, ts := range c.timestamps {
+		profile.TimeDeltas[i] = (ts - prev).Microseconds()
+		prev = ts
+	}
+
+	var walk func(n *CPUProfileNode)
+	walk = func(n *CPUProfileNode) {
+		node := chromeCPUProfileNode{
+			ID: n.nodeID,
+			CallFrame: chromeCallFrame{
+				FunctionName: n.functionName,
+				ScriptID:     strconv.Itoa(n.scriptID),
+				URL:          n.scriptResourceName,
+				// DevTools positions are 0-based
+				LineNumber:   n.lineNumber - 1,
+				ColumnNumber: n.columnNumber - 1,
+			},
+			HitCount:    n.hitCount,
+			DeoptReason: n.bailoutReason,
+		}
+		for _, child := range n.children {
+			node.Children = append(node.Children, child.nodeID)
+		}
+		for _, tick := range n.lineTicks {
+			node.PositionTicks = append(node.PositionTicks, chromePositionTicks{Line: tick.Line, Ticks: tick.HitCount})
+		}
+		profile.Nodes = append(profile.Nodes, node)
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(c.root)
+
+	return json.NewEncoder(w).Encode(profile)
+}
+
 // Deletes the profile and removes it from CpuProfiler's list.
 // All pointers to nodes previously returned become invalid.
 func (c *CPUProfile) Delete() {
@@ -5,7 +5,12 @@
 package v8go_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -53,6 +58,32 @@ func TestCPUProfile(t *testing.T) {
 	}
 }
 
+func TestCPUProfile_GetDuration(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	cpuProfiler := v8.NewCPUProfiler(iso)
+	defer cpuProfiler.Dispose()
+
+	start := time.Now()
+	cpuProfiler.StartProfiling("durationtest")
+	_, err := ctx.RunScript("const end = Date.now() + 200; while (Date.now() < end) {}", "busy.js")
+	fatalIf(t, err)
+	cpuProfile := cpuProfiler.StopProfiling("durationtest")
+	elapsed := time.Since(start)
+	defer cpuProfile.Delete()
+
+	// the profile runs within the measured time and for at least the busy loop
+	duration := cpuProfile.GetDuration()
+	if duration < 200*time.Millisecond || duration > elapsed {
+		t.Errorf("expected a duration between 200ms and %s, got %s", elapsed, duration)
+	}
+}
+
 func TestCPUProfile_Delete(t *testing.T) {
 	t.Parallel()
 
@@ -68,3 +99,125 @@ func TestCPUProfile_Delete(t *testing.T) {
 	// noop when called multiple times
 	cpuProfile.Delete()
 }
+
+func TestCPUProfile_Samples(t *testing.T) {
+	t.Parallel()
+
+	cpuProfile := runProfile(t, "samplestest")
+
+	count := cpuProfile.GetSamplesCount()
+	if count == 0 {
+		t.Fatal("expected samples to be recorded")
+	}
+	var prev time.Duration
+	duration := cpuProfile.GetDuration()
+	for i := 0; i < count; i++ {
+		if cpuProfile.GetSample(i) == nil {
+			t.Fatalf("expected sample %d to have a node", i)
+		}
+		ts := cpuProfile.GetSampleTimestamp(i)
+		if ts < prev {
+			t.Fatalf("expected increasing timestamps, got %s after %s", ts, prev)
+		}
+		if ts < 0 || ts > duration {
+			t.Fatalf("expected sample %d within the profile duration %s, got %s", i, duration, ts)
+		}
+		prev = ts
+	}
+}
+
+func TestCPUProfile_WritePprof(t *testing.T) {
+	t.Parallel()
+
+	cpuProfile := runProfile(t, "pproftest")
+
+	var buf bytes.Buffer
+	fatalIf(t, cpuProfile.WritePprof(&buf))
+	zr, err := gzip.NewReader(&buf)
+	fatalIf(t, err)
+	data, err := ioutil.ReadAll(zr)
+	fatalIf(t, err)
+
+	for _, s := range []string{"samples", "cpu", "nanoseconds", "loop", "script.js"} {
+		if !bytes.Contains(data, []byte(s)) {
+			t.Errorf("expected profile to contain %q", s)
+		}
+	}
+}
+
+func TestCPUProfile_WriteChromeJSON(t *testing.T) {
+	t.Parallel()
+
+	cpuProfile := runProfile(t, "chromejsontest")
+
+	var buf bytes.Buffer
+	fatalIf(t, cpuProfile.WriteChromeJSON(&buf))
+
+	var profile struct {
+		Nodes []struct {
+			ID        int `json:"id"`
+			CallFrame struct {
+				FunctionName string `json:"functionName"`
+				URL          string `json:"url"`
+				LineNumber   int    `json:"lineNumber"`
+			} `json:"callFrame"`
+			Children []int `json:"children"`
+		} `json:"nodes"`
+		StartTime  int64   `json:"startTime"`
+		EndTime    int64   `json:"endTime"`
+		Samples    []int   `json:"samples"`
+		TimeDeltas []int64 `json:"timeDeltas"`
+	}
+	fatalIf(t, json.Unmarshal(buf.Bytes(), &profile))
+
+	if profile.EndTime <= profile.StartTime {
+		t.Errorf("expected end time after start time, got %d and %d", profile.StartTime, profile.EndTime)
+	}
+	if len(profile.Samples) != len(profile.TimeDeltas) {
+		t.Errorf("expected a time delta per sample, got %d and %d", len(profile.Samples), len(profile.TimeDeltas))
+	}
+	elapsed := profile.StartTime
+	for i, delta := range profile.TimeDeltas {
+		if delta < 0 {
+			t.Errorf("expected non-negative time delta %d, got %d", i, delta)
+		}
+		elapsed += delta
+	}
+	if elapsed > profile.EndTime {
+		t.Errorf("expected samples before the end time %d, got %d", profile.EndTime, elapsed)
+	}
+	ids := make(map[int]bool)
+	for _, node := range profile.Nodes {
+		ids[node.ID] = true
+		if node.CallFrame.FunctionName == "loop" && node.CallFrame.LineNumber != 0 {
+			t.Errorf("expected 0-based line number for loop, got %d", node.CallFrame.LineNumber)
+		}
+	}
+	for _, id := range profile.Samples {
+		if !ids[id] {
+			t.Errorf("expected sample node %d to be in the profile", id)
+		}
+	}
+}
+
+func runProfile(t *testing.T, title string) *v8.CPUProfile {
+	t.Helper()
+
+	ctx := v8.NewContextWithOptions(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	cpuProfiler := v8.NewCPUProfiler(iso)
+	defer cpuProfiler.Dispose()
+
+	cpuProfiler.StartProfiling(title)
+	_, err := ctx.RunScript(profileScript, "script.js")
+	fatalIf(t, err)
+	_, err = ctx.RunScript("start(100)", "run.js")
+	fatalIf(t, err)
+	cpuProfile := cpuProfiler.StopProfiling(title)
+	// everything but the V8 profile itself has been copied to Go
+	cpuProfile.Delete()
+	return cpuProfile
+}
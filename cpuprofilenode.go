@@ -5,6 +5,12 @@
 package v8go
 
 type CPUProfileNode struct {
+	// The id of the node, unique within the profile.
+	nodeID int
+
+	// The id of the script from where the function originates.
+	scriptID int
+
 	// The resource name for script from where the function originates.
 	scriptResourceName string
 
@@ -17,6 +23,15 @@ type CPUProfileNode struct {
 	// The number of the column where the function originates.
 	columnNumber int
 
+	// The number of samples taken while this node was on top of the stack.
+	hitCount int
+
+	// The reason the function was not optimized, or empty.
+	bailoutReason string
+
+	// The samples hit counts per source line of the function.
+	lineTicks []CPUProfileLineTick
+
 	// The children node of this node.
 	children []*CPUProfileNode
 
@@ -24,6 +39,25 @@ type CPUProfileNode struct {
 	parent *CPUProfileNode
 }
 
+// CPUProfileLineTick is the number of samples taken on a source line.
+type CPUProfileLineTick struct {
+	// The 1-based number of the source line.
+	Line int
+
+	// The count of samples associated with the source line.
+	HitCount int
+}
+
+// Returns the id of the node, unique within the profile.
+func (c *CPUProfileNode) GetNodeID() int {
+	return c.nodeID
+}
+
+// Returns the id of the script from where the function originates.
+func (c *CPUProfileNode) GetScriptID() int {
+	return c.scriptID
+}
+
 // Returns function name (empty string for anonymous functions.)
 func (c *CPUProfileNode) GetFunctionName() string {
 	return c.functionName
@@ -44,6 +78,22 @@ func (c *CPUProfileNode) GetColumnNumber() int {
 	return c.columnNumber
 }
 
+// Returns the count of samples where the function was currently executing.
+func (c *CPUProfileNode) GetHitCount() int {
+	return c.hitCount
+}
+
+// Returns the bailout reason for the function if the optimization was
+// disabled for it, or an empty string.
+func (c *CPUProfileNode) GetBailoutReason() string {
+	return c.bailoutReason
+}
+
+// Returns the sample hit counts per source line of the function.
+func (c *CPUProfileNode) GetLineTicks() []CPUProfileLineTick {
+	return c.lineTicks
+}
+
 // Retrieves the ancestor node, or nil if the root.
 func (c *CPUProfileNode) GetParent() *CPUProfileNode {
 	return c.parent
@@ -76,6 +76,26 @@ func TestCPUProfileNode(t *testing.T) {
 
 	bazNode := findChild(t, fooNode, "baz")
 	checkNode(t, bazNode, "script.js", "baz", 14, 13)
+
+	if loopNode.GetHitCount() == 0 {
+		t.Error("expected loop to have been sampled")
+	}
+	var ticks int
+	for _, tick := range loopNode.GetLineTicks() {
+		if tick.Line < 1 || tick.Line > 11 {
+			t.Errorf("expected line tick within loop, got line %d", tick.Line)
+		}
+		ticks += tick.HitCount
+	}
+	if ticks != loopNode.GetHitCount() {
+		t.Errorf("expected line ticks to add up to %d, got %d", loopNode.GetHitCount(), ticks)
+	}
+	if loopNode.GetScriptID() == 0 || loopNode.GetScriptID() != startNode.GetScriptID() {
+		t.Errorf("expected nodes of the same script to share a script id, got %d and %d", loopNode.GetScriptID(), startNode.GetScriptID())
+	}
+	if loopNode.GetNodeID() == startNode.GetNodeID() {
+		t.Error("expected distinct node ids")
+	}
 }
 
 func findChild(t *testing.T, node *v8.CPUProfileNode, functionName string) *v8.CPUProfileNode {
@@ -10,13 +10,65 @@ package v8go
 */
 import "C"
 import (
+	"fmt"
+	"math"
 	"time"
 	"unsafe"
 )
 
+// CPUProfilingNamingMode controls how function names are recorded in profiles.
+type CPUProfilingNamingMode int
+
+const (
+	// CPUProfilingStandardNaming uses the function name as given in the source.
+	CPUProfilingStandardNaming CPUProfilingNamingMode = iota
+	// CPUProfilingDebugNaming also uses names inferred by V8 for anonymous
+	// functions, such as the property they are assigned to.
+	CPUProfilingDebugNaming
+)
+
+// CPUProfilingMode controls which line numbers are attributed to samples.
+type CPUProfilingMode int
+
+const (
+	// CPUProfilingLeafNodeLineNumbers records line numbers for the leaf
+	// (currently executing) frame of each sample only.
+	CPUProfilingLeafNodeLineNumbers CPUProfilingMode = iota
+	// CPUProfilingCallerLineNumbers records the line number of the call site
+	// for every frame, so callers are split by the line they call from.
+	CPUProfilingCallerLineNumbers
+)
+
+// CPUProfilerOptions configures a CPUProfiler and the profiles it records.
+type CPUProfilerOptions struct {
+	// NamingMode controls how function names are recorded.
+	NamingMode CPUProfilingNamingMode
+
+	// SamplingInterval is the interval between samples, rounded to
+	// microseconds. The V8 default of 1ms is used when 0.
+	SamplingInterval time.Duration
+
+	// Mode controls which line numbers are attributed to samples.
+	Mode CPUProfilingMode
+
+	// MaxSamples is the maximum number of samples buffered per profile, with
+	// 0 for no limit. Samples past the limit are dropped. It must be between 0
+	// and math.MaxUint32.
+	MaxSamples int
+
+	// DisableSamples drops the individual samples and their timestamps, so
+	// that only the aggregated hit counts are kept on the profile. Samples are
+	// recorded by default, as with NewCPUProfiler, see CPUProfile.GetSample.
+	DisableSamples bool
+}
+
 type CPUProfiler struct {
 	p   *C.CPUProfiler
 	iso *Isolate
+
+	// opts is nil for profilers created with NewCPUProfiler, which use the
+	// V8 defaults and record samples.
+	opts *CPUProfilerOptions
 }
 
 // CPUProfiler is used to control CPU profiling.
@@ -28,6 +80,50 @@ func NewCPUProfiler(iso *Isolate) *CPUProfiler {
 	}
 }
 
+// NewCPUProfilerWithOptions creates a CPUProfiler whose profiles are recorded
+// with the given options. The zero options record profiles like
+// NewCPUProfiler. It panics if MaxSamples or SamplingInterval is out of range.
+func NewCPUProfilerWithOptions(iso *Isolate, opts CPUProfilerOptions) *CPUProfiler {
+	if opts.MaxSamples < 0 || int64(opts.MaxSamples) > math.MaxUint32 {
+		panic(fmt.Sprintf("v8go: MaxSamples out of range: %d", opts.MaxSamples))
+	}
+	if opts.SamplingInterval < 0 || opts.SamplingInterval/time.Microsecond > math.MaxInt32 {
+		panic(fmt.Sprintf("v8go: SamplingInterval out of range: %s", opts.SamplingInterval))
+	}
+	profiler := C.NewCPUProfilerWithNamingMode(iso.ptr, C.int(opts.NamingMode))
+	return &CPUProfiler{
+		p:    profiler,
+		iso:  iso,
+		opts: &opts,
+	}
+}
+
+// SetSamplingInterval changes the default interval between samples, which is
+// used by profiles started without an explicit SamplingInterval. It must be
+// called before any profile is started.
+func (c *CPUProfiler) SetSamplingInterval(interval time.Duration) {
+	if c.p == nil || c.iso.ptr == nil {
+		panic("profiler or isolate are nil")
+	}
+
+	C.CPUProfilerSetSamplingInterval(c.p, C.int(interval/time.Microsecond))
+}
+
+// SetUsePreciseSampling sets whether the sampling thread should busy-wait
+// rather than sleep between samples, for more accurate intervals at the cost
+// of a CPU core. It must be called before any profile is started.
+func (c *CPUProfiler) SetUsePreciseSampling(precise bool) {
+	if c.p == nil || c.iso.ptr == nil {
+		panic("profiler or isolate are nil")
+	}
+
+	var usePrecise C.int
+	if precise {
+		usePrecise = 1
+	}
+	C.CPUProfilerSetUsePreciseSampling(c.p, usePrecise)
+}
+
 // Dispose will dispose the profiler.
 func (c *CPUProfiler) Dispose() {
 	if c.p == nil {
@@ -49,7 +145,25 @@ func (c *CPUProfiler) StartProfiling(title string) {
 	tstr := C.CString(title)
 	defer FreeCPtr(unsafe.Pointer(tstr))
 
-	C.CPUProfilerStartProfiling(c.p, tstr)
+	if c.opts == nil {
+		C.CPUProfilerStartProfiling(c.p, tstr)
+		return
+	}
+
+	maxSamples := C.uint(math.MaxUint32)
+	if c.opts.MaxSamples > 0 {
+		maxSamples = C.uint(c.opts.MaxSamples)
+	}
+	recordSamples := C.int(1)
+	if c.opts.DisableSamples {
+		recordSamples = 0
+	}
+	C.CPUProfilerStartProfilingWithOptions(c.p, tstr, C.CPUProfilingOptions{
+		mode:               C.int(c.opts.Mode),
+		maxSamples:         maxSamples,
+		samplingIntervalUs: C.int(c.opts.SamplingInterval / time.Microsecond),
+		recordSamples:      recordSamples,
+	})
 }
 
 // Stops collecting CPU profile with a given title and returns it.
@@ -64,16 +178,36 @@ func (c *CPUProfiler) StopProfiling(title string) *CPUProfile {
 
 	profile := C.CPUProfilerStopProfiling(c.p, tstr)
 
-	return &CPUProfile{
+	p := &CPUProfile{
 		p:               profile,
 		title:           C.GoString(profile.title),
-		root:            newCPUProfileNode(profile.root, nil),
-		startTimeOffset: time.Duration(profile.startTime) * time.Millisecond,
-		endTimeOffset:   time.Duration(profile.endTime) * time.Millisecond,
+		nodes:           make(map[int]*CPUProfileNode),
+		startTimeOffset: time.Duration(profile.startTime) * time.Microsecond,
+		endTimeOffset:   time.Duration(profile.endTime) * time.Microsecond,
+	}
+	p.root = newCPUProfileNode(profile.root, nil, p.nodes)
+
+	if !hasExtensions {
+		// node ids, hit counts and samples need the newer entry points
+		return p
 	}
+	if count := int(C.CPUProfileGetSamplesCount(profile.ptr)); count > 0 {
+		samples := make([]C.uint, count)
+		timestamps := make([]C.int64_t, count)
+		C.CPUProfileGetSamples(profile.ptr, &samples[0], &timestamps[0], C.int(count))
+		p.samples = make([]int, count)
+		p.timestamps = make([]time.Duration, count)
+		for i := 0; i < count; i++ {
+			p.samples[i] = int(samples[i])
+			// V8 timestamps are in microseconds, like the profile start and end times
+			p.timestamps[i] = time.Duration(timestamps[i]) * time.Microsecond
+		}
+	}
+
+	return p
 }
 
-func newCPUProfileNode(node *C.CPUProfileNode, parent *CPUProfileNode) *CPUProfileNode {
+func newCPUProfileNode(node *C.CPUProfileNode, parent *CPUProfileNode, nodes map[int]*CPUProfileNode) *CPUProfileNode {
 	n := &CPUProfileNode{
 		scriptResourceName: C.GoString(node.scriptResourceName),
 		functionName:       C.GoString(node.functionName),
@@ -81,10 +215,26 @@ func newCPUProfileNode(node *C.CPUProfileNode, parent *CPUProfileNode) *CPUProfi
 		columnNumber:       int(node.columnNumber),
 		parent:             parent,
 	}
+	if hasExtensions {
+		n.nodeID = int(C.CPUProfileNodeGetNodeId(node.ptr))
+		n.scriptID = int(C.CPUProfileNodeGetScriptId(node.ptr))
+		n.bailoutReason = C.GoString(C.CPUProfileNodeGetBailoutReason(node.ptr))
+		n.hitCount = int(C.CPUProfileNodeGetHitCount(node.ptr))
+		nodes[n.nodeID] = n
+
+		if count := C.CPUProfileNodeGetHitLineCount(node.ptr); count > 0 {
+			ticks := make([]C.CPUProfileLineTick, count)
+			if C.CPUProfileNodeGetLineTicks(node.ptr, &ticks[0], count) != 0 {
+				for _, tick := range ticks {
+					n.lineTicks = append(n.lineTicks, CPUProfileLineTick{Line: int(tick.line), HitCount: int(tick.hitCount)})
+				}
+			}
+		}
+	}
 
 	if node.childrenCount > 0 {
 		for _, child := range (*[1 << 28]*C.CPUProfileNode)(unsafe.Pointer(node.children))[:node.childrenCount:node.childrenCount] {
-			n.children = append(n.children, newCPUProfileNode(child, n))
+			n.children = append(n.children, newCPUProfileNode(child, n, nodes))
 		}
 	}
 
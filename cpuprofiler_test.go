@@ -6,6 +6,7 @@ package v8go_test
 
 import (
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -30,6 +31,14 @@ func TestCPUProfiler_Dispose(t *testing.T) {
 		t.Error("expected panic")
 	}
 
+	if recoverPanic(func() { cpuProfiler.SetSamplingInterval(time.Millisecond) }) == nil {
+		t.Error("expected panic")
+	}
+
+	if recoverPanic(func() { cpuProfiler.SetUsePreciseSampling(true) }) == nil {
+		t.Error("expected panic")
+	}
+
 	cpuProfiler = v8.NewCPUProfiler(iso)
 	defer cpuProfiler.Dispose()
 	iso.Dispose()
@@ -77,6 +86,64 @@ func TestCPUProfiler(t *testing.T) {
 	}
 }
 
+func TestCPUProfilerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	_, err := ctx.RunScript(profileScript, "script.js")
+	fatalIf(t, err)
+
+	tests := [...]struct {
+		name    string
+		opts    v8.CPUProfilerOptions
+		samples func(n int) bool
+	}{
+		{"Default", v8.CPUProfilerOptions{}, func(n int) bool { return n > 0 }},
+		{"SamplingInterval", v8.CPUProfilerOptions{SamplingInterval: 50 * time.Microsecond}, func(n int) bool { return n > 10 }},
+		{"MaxSamples", v8.CPUProfilerOptions{SamplingInterval: 50 * time.Microsecond, MaxSamples: 10}, func(n int) bool { return n > 0 && n <= 10 }},
+		{"DisableSamples", v8.CPUProfilerOptions{NamingMode: v8.CPUProfilingDebugNaming, Mode: v8.CPUProfilingCallerLineNumbers, DisableSamples: true}, func(n int) bool { return n == 0 }},
+	}
+
+	for _, tt := range tests {
+		cpuProfiler := v8.NewCPUProfilerWithOptions(iso, tt.opts)
+		cpuProfiler.SetUsePreciseSampling(true)
+		cpuProfiler.StartProfiling(tt.name)
+		_, err := ctx.RunScript("start(50)", "run.js")
+		fatalIf(t, err)
+		cpuProfile := cpuProfiler.StopProfiling(tt.name)
+
+		if n := cpuProfile.GetSamplesCount(); !tt.samples(n) {
+			t.Errorf("%s: unexpected number of samples %d", tt.name, n)
+		}
+		if cpuProfile.GetTopDownRoot().GetChildrenCount() == 0 {
+			t.Errorf("%s: expected call tree to be recorded", tt.name)
+		}
+		cpuProfile.Delete()
+		cpuProfiler.Dispose()
+	}
+}
+
+func TestCPUProfilerWithOptions_panic(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	for _, opts := range []v8.CPUProfilerOptions{
+		{MaxSamples: -1},
+		{SamplingInterval: -time.Millisecond},
+	} {
+		opts := opts
+		if recoverPanic(func() { v8.NewCPUProfilerWithOptions(iso, opts) }) == nil {
+			t.Errorf("expected a panic for %+v", opts)
+		}
+	}
+}
+
 const profileScript = `function loop(timeout) {
   this.mmm = 0;
   var start = Date.now();
@@ -0,0 +1,39 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// NewDate creates a Date for the given time. JS dates have a millisecond
+// precision, so t is truncated to the millisecond.
+func NewDate(ctx *Context, t time.Time) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctx.iso.checkThread()
+	ms := float64(t.Unix())*1e3 + float64(t.Nanosecond()/int(time.Millisecond))
+	return valueResult(ctx.iso, C.NewValueDate(ctx.ptr, C.double(ms)))
+}
+
+// Time returns the time of a Date, in the local time zone. It returns the zero
+// time for values that are not a Date and for invalid dates.
+func (v *Value) Time() time.Time {
+	if !v.IsDate() {
+		return time.Time{}
+	}
+	ms := float64(C.ValueToDateTime(v.ptr))
+	if math.IsNaN(ms) {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(ms / 1e3)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e3))*int64(time.Millisecond))
+}
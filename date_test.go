@@ -0,0 +1,72 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewDate(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	if _, err := v8.NewDate(nil, time.Now()); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+
+	tm := time.Date(2021, 3, 4, 5, 6, 7, 8e6+999, time.UTC)
+	val, err := v8.NewDate(ctx, tm)
+	fatalIf(t, err)
+	if !val.IsDate() {
+		t.Fatal("expected Date")
+	}
+	fatalIf(t, ctx.Global().Set("d", val))
+	iso, err := ctx.RunScript("d.toISOString()", "date.js")
+	fatalIf(t, err)
+	if iso.String() != "2021-03-04T05:06:07.008Z" {
+		t.Errorf("expected 2021-03-04T05:06:07.008Z, got %s", iso.String())
+	}
+	if got := val.Time(); !got.Equal(tm.Truncate(time.Millisecond)) {
+		t.Errorf("expected %s, got %s", tm.Truncate(time.Millisecond), got)
+	}
+
+	before := time.Date(1969, 12, 31, 23, 59, 58, 500e6, time.UTC)
+	val, err = v8.NewDate(ctx, before)
+	fatalIf(t, err)
+	if got := val.Time(); !got.Equal(before) {
+		t.Errorf("expected %s, got %s", before, got)
+	}
+}
+
+func TestValueTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source   string
+		expected time.Time
+	}{
+		{"new Date(0)", time.Unix(0, 0)},
+		{"new Date(Date.UTC(2000, 0, 1, 0, 0, 0, 250))", time.Date(2000, 1, 1, 0, 0, 0, 250e6, time.UTC)},
+		{"new Date(NaN)", time.Time{}},
+		{"'2000-01-01'", time.Time{}},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "time.js")
+		fatalIf(t, err)
+		if got := val.Time(); !got.Equal(tt.expected) {
+			t.Errorf("%s: expected %s, got %s", tt.source, tt.expected, got)
+		}
+	}
+}
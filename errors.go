@@ -8,11 +8,54 @@ package v8go
 // #include "v8go.h"
 import "C"
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"runtime/debug"
 	"unsafe"
 )
 
+// ErrHeapLimitExceeded is returned when script execution was terminated because
+// the Isolate reached its heap limit, see IsolateOptions.
+var ErrHeapLimitExceeded = errors.New("v8go: heap limit exceeded")
+
+// ErrExecutionTerminated is returned when script execution was terminated because
+// the context.Context it was run with got canceled. It wraps context.Canceled,
+// so errors.Is matches either.
+var ErrExecutionTerminated error = executionTerminatedError{}
+
+type executionTerminatedError struct{}
+
+func (executionTerminatedError) Error() string { return "v8go: execution terminated" }
+
+func (executionTerminatedError) Unwrap() error { return context.Canceled }
+
+// ErrDeadlineExceeded is returned when script execution was terminated because
+// the deadline of the context.Context it was run with passed. It wraps
+// context.DeadlineExceeded, so errors.Is matches either.
+var ErrDeadlineExceeded error = deadlineExceededError{}
+
+type deadlineExceededError struct{}
+
+func (deadlineExceededError) Error() string { return "v8go: execution deadline exceeded" }
+
+func (deadlineExceededError) Unwrap() error { return context.DeadlineExceeded }
+
+// contextCauseError is ErrDeadlineExceeded or ErrExecutionTerminated for a
+// context.Context that was canceled with a cause, which it wraps.
+type contextCauseError struct {
+	err   error
+	cause error
+}
+
+func (e *contextCauseError) Error() string { return e.err.Error() + ": " + e.cause.Error() }
+
+func (e *contextCauseError) Is(target error) bool { return errors.Is(e.err, target) }
+
+func (e *contextCauseError) Unwrap() error { return e.cause }
+
 // JSError is an error that is returned if there is are any
 // JavaScript exceptions handled in the context. When used with the fmt
 // verb `%+v`, will output the JavaScript stack trace, if available.
@@ -22,12 +65,18 @@ type JSError struct {
 	StackTrace string
 }
 
-func newJSError(rtnErr C.RtnError) error {
+func newJSError(iso *Isolate, rtnErr C.RtnError) error {
 	err := &JSError{
 		Message:    C.GoString(rtnErr.msg),
 		Location:   C.GoString(rtnErr.location),
 		StackTrace: C.GoString(rtnErr.stack),
 	}
+	// the thrown value is kept by the isolate, as RtnError cannot hold it
+	if hasExtensions && iso != nil && iso.ptr != nil {
+		if ptr := C.IsolateTakeException(iso.ptr); ptr != nil {
+			iso.setException(err, NewValueStruct(ptr, iso))
+		}
+	}
 	FreeModuleCPtr(unsafe.Pointer(rtnErr.msg))
 	FreeModuleCPtr(unsafe.Pointer(rtnErr.location))
 	FreeModuleCPtr(unsafe.Pointer(rtnErr.stack))
@@ -62,3 +111,122 @@ func (e *JSError) Format(s fmt.State, verb rune) {
 		fmt.Fprintf(s, "%q", e.Message)
 	}
 }
+
+// JSException is an error holding a value thrown in JS, such as an Error
+// object, along with the JSError it was reported as. It wraps the JSError, so
+// that errors.As finds either. A FunctionCallbackWithError that returns a
+// JSException throws its Value.
+type JSException struct {
+	Value *Value
+	Err   *JSError
+}
+
+func (e *JSException) Error() string {
+	if e.Err == nil {
+		return "v8go: exception thrown in JS"
+	}
+	return e.Err.Error()
+}
+
+func (e *JSException) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// Exception returns the JSException holding the value thrown in JS for err,
+// when err is or wraps a JSError returned by the ISO for a JS exception, and
+// nil otherwise.
+func (i *Isolate) Exception(err error) *JSException {
+	var exc *JSException
+	if errors.As(err, &exc) {
+		return exc
+	}
+	var jsErr *JSError
+	if !errors.As(err, &jsErr) {
+		return nil
+	}
+	i.exceptionsLock.Lock()
+	defer i.exceptionsLock.Unlock()
+	if val, ok := i.exceptions[jsErr]; ok {
+		return &JSException{Value: val, Err: jsErr}
+	}
+	return nil
+}
+
+// setException records val as the value thrown for err, for as long as err is
+// referenced.
+func (i *Isolate) setException(err *JSError, val *Value) {
+	i.exceptionsLock.Lock()
+	defer i.exceptionsLock.Unlock()
+	if i.exceptions == nil {
+		i.exceptions = make(map[*JSError]*Value)
+	}
+	i.exceptions[err] = val
+	runtime.SetFinalizer(err, func(err *JSError) {
+		i.exceptionsLock.Lock()
+		defer i.exceptionsLock.Unlock()
+		delete(i.exceptions, err)
+	})
+}
+
+// errorKind is the constructor of an Error created with newErrorValue.
+type errorKind int
+
+const (
+	errorKindError errorKind = iota
+	errorKindTypeError
+	errorKindRangeError
+	errorKindReferenceError
+	errorKindSyntaxError
+)
+
+// newErrorValue creates a JS Error object of the given kind, with a stack
+// trace captured at the current JS location.
+func newErrorValue(iso *Isolate, kind errorKind, msg string) *Value {
+	if !hasExtensions {
+		// the older entry points cannot create an Error, so the message is thrown
+		val, _ := NewValue(iso, msg)
+		return val
+	}
+	cmsg := C.CString(msg)
+	defer FreeCPtr(unsafe.Pointer(cmsg))
+	return NewValueStruct(C.NewValueError(iso.ptr, C.int(kind), cmsg), iso)
+}
+
+// throwError schedules err to be thrown when returning to JS: the original
+// exception for a JSError, and an Error with the message of err otherwise.
+func (i *Isolate) throwError(err error) *Value {
+	if exc := i.Exception(err); exc != nil && exc.Value != nil {
+		return i.ThrowException(exc.Value)
+	}
+	return i.ThrowException(newErrorValue(i, errorKindError, err.Error()))
+}
+
+// throwPanic schedules an Error to be thrown when returning to JS for a value
+// recovered from a panic in a Go callback. The stack of the Error ends with
+// the Go stack of the panic.
+func (i *Isolate) throwPanic(r interface{}) *Value {
+	msg := fmt.Sprintf("v8go: panic in Go callback: %v", r)
+	goStack := "\n\nGo stack:\n" + string(debug.Stack())
+	errVal := newErrorValue(i, errorKindError, msg)
+	obj, err := errVal.AsObject()
+	if err == nil {
+		var stack *Value
+		if stack, err = obj.Get("stack"); err == nil {
+			err = obj.Set("stack", stack.String()+goStack)
+		}
+	}
+	if err != nil {
+		// the stack cannot be extended, so keep the Go stack in the message
+		errVal = newErrorValue(i, errorKindError, msg+goStack)
+	}
+	return i.ThrowException(errVal)
+}
+
+// panicError converts a value recovered from a panic in a Go callback to an
+// error that includes the Go stack of the panic.
+func panicError(r interface{}) error {
+	return fmt.Errorf("v8go: panic in Go callback: %v\n\nGo stack:\n%s", r, debug.Stack())
+}
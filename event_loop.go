@@ -0,0 +1,348 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// EventLoop drives asynchronous JavaScript for a Context. It installs the
+// setTimeout, setInterval, setImmediate, their clear counterparts and
+// queueMicrotask globals, and runs timers and tasks posted from Go as
+// macrotasks, draining the microtask queue after each one.
+//
+// RunOnce and RunUntilIdle must be called from the goroutine that owns the
+// Context; Post may be called from any goroutine.
+type EventLoop struct {
+	ctx *Context
+
+	mu    sync.Mutex
+	tasks []func()
+	holds int
+	wake  chan struct{}
+
+	timerSeq int32
+	seq      uint64
+	timers   timerQueue
+	active   map[int32]*timer
+}
+
+type timer struct {
+	id       int32
+	seq      uint64
+	when     time.Time
+	interval time.Duration
+	repeat   bool
+	fn       *Function
+	args     []Valuer
+	index    int
+}
+
+// NewEventLoop creates an EventLoop for ctx and installs the timer globals on
+// its global object.
+func NewEventLoop(ctx *Context) (*EventLoop, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	l := &EventLoop{
+		ctx:    ctx,
+		wake:   make(chan struct{}, 1),
+		active: make(map[int32]*timer),
+	}
+	iso := ctx.iso
+	globals := map[string]FunctionCallback{
+		"setTimeout":     l.setTimer(false),
+		"setInterval":    l.setTimer(true),
+		"setImmediate":   l.setImmediate,
+		"clearTimeout":   l.clearTimer,
+		"clearInterval":  l.clearTimer,
+		"clearImmediate": l.clearTimer,
+		"queueMicrotask": l.queueMicrotask,
+	}
+	for name, cb := range globals {
+		fn := NewFunctionTemplate(iso, cb).GetFunction(ctx)
+		if err := ctx.Global().Set(name, fn); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Post queues task to be run as a macrotask by the event loop. It is safe to
+// call Post from any goroutine, and the task is always run on the goroutine
+// that runs the loop.
+func (l *EventLoop) Post(task func()) {
+	l.mu.Lock()
+	l.tasks = append(l.tasks, task)
+	l.mu.Unlock()
+	l.signal()
+}
+
+// Hold keeps RunUntilIdle running, even with no timers pending and no tasks
+// queued, until release is called. It is meant for Go work that will Post a
+// task once done, such as a request that resolves a Promise. Hold may be
+// called from any goroutine, and release may be called more than once.
+func (l *EventLoop) Hold() (release func()) {
+	l.mu.Lock()
+	l.holds++
+	l.mu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.holds--
+			l.mu.Unlock()
+			l.signal()
+		})
+	}
+}
+
+func (l *EventLoop) signal() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunOnce runs a single macrotask, if one is ready, and then drains the
+// microtask queue. Posted tasks run before due timers. It reports whether a
+// macrotask was run; the error is from a timer callback that threw.
+func (l *EventLoop) RunOnce() (bool, error) {
+	return l.runOnce(context.Background())
+}
+
+// RunUntilIdle runs macrotasks, waiting for timers to become due, until no
+// timers are pending, no tasks are queued and every Hold is released. Script
+// execution is terminated and RunUntilIdle returns when ctx is done; see
+// (*Context).RunScriptContext.
+func (l *EventLoop) RunUntilIdle(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		ran, err := l.runOnce(ctx)
+		if err != nil {
+			return err
+		}
+		if ran {
+			continue
+		}
+
+		l.mu.Lock()
+		idle := len(l.tasks) == 0 && len(l.timers) == 0 && l.holds == 0
+		ready := len(l.tasks) > 0
+		// with only holds outstanding, wait for a posted task or a release
+		var due <-chan time.Time
+		var t *time.Timer
+		if !ready && len(l.timers) > 0 {
+			wait := time.Until(l.timers[0].when)
+			if wait <= 0 {
+				ready = true
+			} else {
+				t = time.NewTimer(wait)
+				due = t.C
+			}
+		}
+		l.mu.Unlock()
+		if idle {
+			return nil
+		}
+		if ready {
+			continue
+		}
+
+		select {
+		case <-due:
+		case <-l.wake:
+		case <-ctx.Done():
+		}
+		if t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// Close stops all pending timers and drops queued tasks.
+func (l *EventLoop) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tasks = nil
+	l.timers = nil
+	l.active = make(map[int32]*timer)
+}
+
+func (l *EventLoop) runOnce(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	if len(l.tasks) > 0 {
+		task := l.tasks[0]
+		l.tasks[0] = nil
+		l.tasks = l.tasks[1:]
+		l.mu.Unlock()
+		task()
+		l.ctx.PerformMicrotaskCheckpoint()
+		return true, nil
+	}
+	if len(l.timers) == 0 || l.timers[0].when.After(time.Now()) {
+		l.mu.Unlock()
+		return false, nil
+	}
+	t := heap.Pop(&l.timers).(*timer)
+	if t.repeat {
+		t.when = time.Now().Add(t.interval)
+		t.seq = l.nextSeq()
+		heap.Push(&l.timers, t)
+	} else {
+		delete(l.active, t.id)
+	}
+	l.mu.Unlock()
+
+	_, err := t.fn.CallContext(ctx, Undefined(l.ctx.iso), t.args...)
+	l.ctx.PerformMicrotaskCheckpoint()
+	return true, err
+}
+
+func (l *EventLoop) nextSeq() uint64 {
+	l.seq++
+	return l.seq
+}
+
+func (l *EventLoop) addTimer(fn *Function, delay time.Duration, repeat bool, args []Valuer) int32 {
+	if delay < 0 {
+		delay = 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timerSeq++
+	t := &timer{
+		id:       l.timerSeq,
+		seq:      l.nextSeq(),
+		when:     time.Now().Add(delay),
+		interval: delay,
+		repeat:   repeat,
+		fn:       fn,
+		args:     args,
+	}
+	l.active[t.id] = t
+	heap.Push(&l.timers, t)
+	return t.id
+}
+
+func (l *EventLoop) setTimer(repeat bool) FunctionCallback {
+	return func(info *FunctionCallbackInfo) *Value {
+		args := info.Args()
+		fn, err := callbackArg(info)
+		if err != nil {
+			return throwTypeError(info, err)
+		}
+		var delay time.Duration
+		if len(args) > 1 {
+			delay = time.Duration(args[1].Number() * float64(time.Millisecond))
+		}
+		if repeat && delay < time.Millisecond {
+			// an interval of 0 would keep the loop from ever waiting
+			delay = time.Millisecond
+		}
+		var rest []Valuer
+		if len(args) > 2 {
+			for _, arg := range args[2:] {
+				rest = append(rest, arg)
+			}
+		}
+		id, _ := NewValue(info.ctx.iso, l.addTimer(fn, delay, repeat, rest))
+		return id
+	}
+}
+
+func (l *EventLoop) setImmediate(info *FunctionCallbackInfo) *Value {
+	fn, err := callbackArg(info)
+	if err != nil {
+		return throwTypeError(info, err)
+	}
+	var rest []Valuer
+	for _, arg := range info.Args()[1:] {
+		rest = append(rest, arg)
+	}
+	id, _ := NewValue(info.ctx.iso, l.addTimer(fn, 0, false, rest))
+	return id
+}
+
+func (l *EventLoop) clearTimer(info *FunctionCallbackInfo) *Value {
+	if len(info.Args()) == 0 {
+		return nil
+	}
+	id := info.Args()[0].Int32()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.active[id]; ok {
+		delete(l.active, id)
+		if t.index >= 0 {
+			heap.Remove(&l.timers, t.index)
+		}
+	}
+	return nil
+}
+
+func (l *EventLoop) queueMicrotask(info *FunctionCallbackInfo) *Value {
+	fn, err := callbackArg(info)
+	if err != nil {
+		return throwTypeError(info, err)
+	}
+	info.ctx.iso.EnqueueMicrotask(fn)
+	return nil
+}
+
+func callbackArg(info *FunctionCallbackInfo) (*Function, error) {
+	if len(info.Args()) == 0 {
+		return nil, errors.New("callback must be a function")
+	}
+	fn, err := info.Args()[0].AsFunction()
+	if err != nil {
+		return nil, errors.New("callback must be a function")
+	}
+	return fn, nil
+}
+
+func throwTypeError(info *FunctionCallbackInfo, err error) *Value {
+	iso := info.ctx.iso
+	return iso.ThrowException(newErrorValue(iso, errorKindTypeError, err.Error()))
+}
+
+// timerQueue is a min-heap of timers ordered by due time, then creation order.
+type timerQueue []*timer
+
+func (q timerQueue) Len() int { return len(q) }
+
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].when.Equal(q[j].when) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].when.Before(q[j].when)
+}
+
+func (q timerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timerQueue) Push(x interface{}) {
+	t := x.(*timer)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *timerQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*q = old[:n-1]
+	return t
+}
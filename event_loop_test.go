@@ -0,0 +1,178 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestEventLoopTimers(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	loop, err := v8.NewEventLoop(ctx)
+	fatalIf(t, err)
+	defer loop.Close()
+
+	_, err = ctx.RunScript(`
+		var log = [];
+		setTimeout((a, b) => log.push('timeout ' + a + b), 20, 'x', 'y');
+		setTimeout(() => log.push('first'), 0);
+		const cleared = setTimeout(() => log.push('cleared'), 5);
+		clearTimeout(cleared);
+		let n = 0;
+		const interval = setInterval(() => {
+			log.push('interval ' + (++n));
+			if (n === 3) clearInterval(interval);
+		}, 1);
+		setImmediate(() => {
+			log.push('immediate');
+			Promise.resolve().then(() => log.push('microtask'));
+			queueMicrotask(() => log.push('queued microtask'));
+		});
+	`, "timers.js")
+	fatalIf(t, err)
+
+	fatalIf(t, loop.RunUntilIdle(context.Background()))
+
+	val, err := ctx.RunScript("log.join(',')", "log.js")
+	fatalIf(t, err)
+	expected := "first,immediate,microtask,queued microtask,interval 1,interval 2,interval 3,timeout xy"
+	if val.String() != expected {
+		t.Errorf("expected %q, got %q", expected, val.String())
+	}
+}
+
+func TestEventLoopPost(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	loop, err := v8.NewEventLoop(ctx)
+	fatalIf(t, err)
+	defer loop.Close()
+
+	ran, err := loop.RunOnce()
+	fatalIf(t, err)
+	if ran {
+		t.Error("expected no macrotask to run")
+	}
+
+	_, err = ctx.RunScript("var posted = []; setTimeout(() => {}, 100)", "post.js")
+	fatalIf(t, err)
+	go func() {
+		loop.Post(func() {
+			ctx.RunScript("posted.push('from go')", "task.js")
+		})
+	}()
+	fatalIf(t, loop.RunUntilIdle(context.Background()))
+
+	val, err := ctx.RunScript("posted.join(',')", "posted.js")
+	fatalIf(t, err)
+	if val.String() != "from go" {
+		t.Errorf("expected posted task to run, got %q", val.String())
+	}
+}
+
+func TestEventLoopErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	loop, err := v8.NewEventLoop(ctx)
+	fatalIf(t, err)
+	defer loop.Close()
+
+	val, err := ctx.RunScript("try { setTimeout('not a function') } catch (e) { e instanceof TypeError && e.message }", "invalid.js")
+	fatalIf(t, err)
+	if val.String() != "callback must be a function" {
+		t.Errorf("expected a TypeError for a non function callback, got %q", val.String())
+	}
+
+	_, err = ctx.RunScript("setTimeout(() => { throw new Error('boom') })", "throw.js")
+	fatalIf(t, err)
+	var jsErr *v8.JSError
+	if err := loop.RunUntilIdle(context.Background()); !errors.As(err, &jsErr) {
+		t.Errorf("expected JSError, got %v", err)
+	}
+
+	_, err = ctx.RunScript("setInterval(() => {}, 1)", "forever.js")
+	fatalIf(t, err)
+	deadline, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := loop.RunUntilIdle(deadline); !errors.Is(err, v8.ErrDeadlineExceeded) {
+		t.Errorf("expected ErrDeadlineExceeded, got %v", err)
+	}
+}
+
+func TestEventLoopHold(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	loop, err := v8.NewEventLoop(ctx)
+	fatalIf(t, err)
+	defer loop.Close()
+
+	_, err = ctx.RunScript("var done = false", "hold.js")
+	fatalIf(t, err)
+	release := loop.Hold()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		loop.Post(func() {
+			ctx.RunScript("done = true", "task.js")
+		})
+		release()
+		release()
+	}()
+	fatalIf(t, loop.RunUntilIdle(context.Background()))
+
+	val, err := ctx.RunScript("done", "done.js")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected RunUntilIdle to wait for the held work")
+	}
+}
+
+func TestEventLoopIntervalClamp(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	loop, err := v8.NewEventLoop(ctx)
+	fatalIf(t, err)
+	defer loop.Close()
+
+	_, err = ctx.RunScript("var ticks = 0; setInterval(() => ticks++, 0)", "interval.js")
+	fatalIf(t, err)
+	deadline, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := loop.RunUntilIdle(deadline); !errors.Is(err, v8.ErrDeadlineExceeded) {
+		t.Errorf("expected ErrDeadlineExceeded, got %v", err)
+	}
+
+	val, err := ctx.RunScript("ticks", "ticks.js")
+	fatalIf(t, err)
+	// the interval is clamped to 1ms rather than spinning
+	if n := val.Int32(); n < 1 || n > 25 {
+		t.Errorf("expected about 20 ticks, got %d", n)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "v8go.h"
+import "C"
+import (
+	"runtime"
+	"sync"
+)
+
+// executor runs the tasks of an ISO one at a time on a goroutine that is
+// locked to its own OS thread.
+type executor struct {
+	threadID uint64
+
+	mu      sync.Mutex
+	tasks   []func()
+	stopped bool
+	wake    chan struct{}
+	// exited is closed once the executor runs no more tasks, after stop.
+	exited chan struct{}
+}
+
+func currentThreadID() uint64 {
+	return uint64(C.V8GoCurrentThreadID())
+}
+
+func newExecutor() *executor {
+	e := &executor{wake: make(chan struct{}, 1), exited: make(chan struct{})}
+	started := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		e.threadID = currentThreadID()
+		close(started)
+		defer close(e.exited)
+		e.loop()
+	}()
+	<-started
+	return e
+}
+
+func (e *executor) loop() {
+	for range e.wake {
+		for {
+			e.mu.Lock()
+			if e.stopped {
+				e.mu.Unlock()
+				return
+			}
+			if len(e.tasks) == 0 {
+				e.mu.Unlock()
+				break
+			}
+			task := e.tasks[0]
+			e.tasks[0] = nil
+			e.tasks = e.tasks[1:]
+			e.mu.Unlock()
+			task()
+		}
+	}
+}
+
+func (e *executor) post(task func()) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return false
+	}
+	e.tasks = append(e.tasks, task)
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// stop drops the queued tasks and stops the executor once the running task,
+// if any, returns. Run fails for the dropped tasks once exited is closed.
+func (e *executor) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return
+	}
+	e.stopped = true
+	e.tasks = nil
+	close(e.wake)
+}
+
+func (e *executor) onThread() bool {
+	return currentThreadID() == e.threadID
+}
+
+func (i *Isolate) getExecutor() *executor {
+	i.executorLock.Lock()
+	defer i.executorLock.Unlock()
+	if i.executor == nil {
+		i.executor = newExecutor()
+	}
+	return i.executor
+}
+
+// Run calls fn on the executor of the ISO and waits for it to return.
+// The executor is a goroutine locked to its own OS thread that serializes
+// all tasks given to Run and Post, which makes it safe to use the ISO from
+// many goroutines. When called from the executor itself, e.g. from within a
+// FunctionCallback, fn is called directly. A panic in fn is re-raised in
+// the caller.
+func (i *Isolate) Run(fn func()) {
+	e := i.getExecutor()
+	if e.onThread() {
+		fn()
+		return
+	}
+	done := make(chan interface{}, 1)
+	ok := e.post(func() {
+		defer func() {
+			done <- recover()
+		}()
+		fn()
+	})
+	if !ok {
+		panic("Isolate has been disposed")
+	}
+	var r interface{}
+	select {
+	case r = <-done:
+	case <-e.exited:
+		// fn either ran before the executor stopped or was dropped
+		select {
+		case r = <-done:
+		default:
+			panic("Isolate has been disposed")
+		}
+	}
+	if r != nil {
+		panic(r)
+	}
+}
+
+// Post queues fn to be called on the executor of the ISO without waiting
+// for it. Tasks are run in the order they were posted. Tasks that are still
+// queued when the ISO is disposed are dropped, and Run panics for them.
+func (i *Isolate) Post(fn func()) {
+	if !i.getExecutor().post(fn) {
+		panic("Isolate has been disposed")
+	}
+}
+
+// stopExecutor stops the executor of the ISO and returns it, or returns nil
+// when the ISO never had one.
+func (i *Isolate) stopExecutor() *executor {
+	i.executorLock.Lock()
+	defer i.executorLock.Unlock()
+	if i.executor == nil {
+		// keep Run and Post from starting an executor for a disposed ISO
+		i.executor = &executor{stopped: true, exited: make(chan struct{})}
+		close(i.executor.exited)
+		return nil
+	}
+	i.executor.stop()
+	return i.executor
+}
+
+// checkThread panics if CheckThread is enabled and the ISO is used off the
+// thread of its executor. An ISO only gets an executor on its first Run or
+// Post: before that, goroutines are not tied to an OS thread, so the ISO is
+// not checked at all.
+func (i *Isolate) checkThread() {
+	if !CheckThread || i == nil {
+		return
+	}
+	i.executorLock.Lock()
+	e := i.executor
+	i.executorLock.Unlock()
+	if e != nil && !e.onThread() {
+		panic("v8go: Isolate used off its executor thread; access it through Isolate.Run or Isolate.Post")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestIsolateRun(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	var ctx *v8.Context
+	iso.Run(func() {
+		global := v8.NewObjectTemplate(iso)
+		// Run from within a callback is called directly instead of deadlocking
+		global.Set("nested", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+			var val *v8.Value
+			iso.Run(func() { val, _ = v8.NewValue(iso, "nested") })
+			return val
+		}))
+		ctx = v8.NewContextWithOptions(iso, global)
+		ctx.RunScript("var counter = 0", "counter.js")
+	})
+	defer iso.Run(ctx.Close)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iso.Run(func() {
+				ctx.RunScript("counter++", "increment.js")
+			})
+		}()
+	}
+	wg.Wait()
+
+	var counter int32
+	var nested string
+	var err error
+	iso.Run(func() {
+		var val *v8.Value
+		if val, err = ctx.RunScript("counter", "counter.js"); err != nil {
+			return
+		}
+		counter = val.Int32()
+		if val, err = ctx.RunScript("nested()", "nested.js"); err != nil {
+			return
+		}
+		nested = val.String()
+	})
+	// t.FailNow must not be called from the executor goroutine
+	fatalIf(t, err)
+	if counter != 20 {
+		t.Errorf("expected counter to be 20, got %d", counter)
+	}
+	if nested != "nested" {
+		t.Errorf("expected %q, got %q", "nested", nested)
+	}
+}
+
+func TestIsolatePost(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	var order []int
+	done := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		i := i
+		iso.Post(func() { order = append(order, i) })
+	}
+	iso.Post(func() { close(done) })
+	<-done
+
+	for i, v := range order {
+		if i != v {
+			t.Fatalf("expected tasks to run in order, got %v", order)
+		}
+	}
+}
+
+func TestIsolateRun_Panic(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if r := recoverPanic(func() { iso.Run(func() { panic("boom") }) }); r != "boom" {
+		t.Errorf("expected panic to be re-raised, got %v", r)
+	}
+
+	// the executor keeps running after a panic
+	var ran bool
+	iso.Run(func() { ran = true })
+	if !ran {
+		t.Error("expected task to run")
+	}
+}
+
+func TestIsolateRun_DisposedWhileQueued(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	iso.Post(func() {
+		close(started)
+		<-release
+		iso.Dispose()
+	})
+	<-started
+
+	queued := make(chan interface{})
+	go func() {
+		queued <- recoverPanic(func() { iso.Run(func() {}) })
+	}()
+	// dispose once the Run above had time to be queued behind the running task
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case r := <-queued:
+		if r == nil {
+			t.Error("expected Run of a dropped task to panic")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the Isolate was disposed")
+	}
+}
+
+func TestIsolateDispose_WhilePostRunning(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	started := make(chan struct{})
+	var result int32
+	iso.Post(func() {
+		ctx := v8.NewContextWithOptions(iso)
+		defer ctx.Close()
+		close(started)
+		// keep the ISO busy while Dispose is called from the test goroutine
+		time.Sleep(50 * time.Millisecond)
+		val, err := ctx.RunScript(`let n = 0; for (let i = 0; i < 1e6; i++) n += i % 3; n`, "busy.js")
+		if err == nil {
+			result = val.Int32()
+		}
+	})
+	<-started
+
+	iso.Dispose()
+	if result == 0 {
+		t.Error("expected Dispose to wait for the running task")
+	}
+}
+
+func TestIsolateRun_Disposed(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	iso.Run(func() {})
+	iso.Dispose()
+
+	if recoverPanic(func() { iso.Run(func() {}) }) == nil {
+		t.Error("expected panic")
+	}
+	if recoverPanic(func() { iso.Post(func() {}) }) == nil {
+		t.Error("expected panic")
+	}
+}
@@ -7,6 +7,8 @@ package v8go
 // #include "v8go.h"
 import "C"
 import (
+	"context"
+	"errors"
 	"unsafe"
 )
 
@@ -15,8 +17,32 @@ type Function struct {
 	*Value
 }
 
+// NewFunction creates a function in the given context that calls callback.
+// Unlike the callback of a FunctionTemplate, which is kept until the isolate is
+// disposed, callback is released once the function is garbage collected, which
+// suits one-off closures.
+func NewFunction(ctx *Context, callback FunctionCallback) (*Function, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if callback == nil {
+		return nil, errors.New("v8go: FunctionCallback is required")
+	}
+	iso := ctx.iso
+	iso.checkThread()
+	ref := iso.registerCallback(callback)
+	val, err := valueResult(iso, C.NewFunction(ctx.ptr, C.int(ref)))
+	if err != nil {
+		iso.unregisterCallback(ref)
+		return nil, err
+	}
+	iso.setWeak(val, func() { iso.unregisterCallback(ref) })
+	return &Function{val}, nil
+}
+
 // Call this JavaScript function with the given arguments.
 func (fn *Function) Call(recv Valuer, args ...Valuer) (*Value, error) {
+	fn.ISO.checkThread()
 	var argptr *C.ValuePtr
 	if len(args) > 0 {
 		var cArgs = make([]C.ValuePtr, len(args))
@@ -29,8 +55,18 @@ func (fn *Function) Call(recv Valuer, args ...Valuer) (*Value, error) {
 	return valueResult(fn.ISO, rtn)
 }
 
+// CallContext calls this JavaScript function like Call, but terminates the execution
+// when ctx is done. The error is ErrDeadlineExceeded if the deadline of ctx passed,
+// ErrExecutionTerminated if ctx was canceled and `JSError` otherwise.
+func (fn *Function) CallContext(ctx context.Context, recv Valuer, args ...Valuer) (*Value, error) {
+	return fn.ISO.runContext(ctx, func() (*Value, error) {
+		return fn.Call(recv, args...)
+	})
+}
+
 // Invoke a constructor function to create an object instance.
 func (fn *Function) NewInstance(args ...Valuer) (*Object, error) {
+	fn.ISO.checkThread()
 	var argptr *C.ValuePtr
 	if len(args) > 0 {
 		var cArgs = make([]C.ValuePtr, len(args))
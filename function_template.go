@@ -9,6 +9,7 @@ package v8go
 import "C"
 import (
 	"fmt"
+	"sync"
 	"time"
 	"unsafe"
 )
@@ -16,11 +17,20 @@ import (
 // FunctionCallback is a callback that is executed in Go when a function is executed in JS.
 type FunctionCallback func(info *FunctionCallbackInfo) *Value
 
+// FunctionCallbackWithError is a callback that is executed in Go when a function
+// is executed in JS, and that can return an error to throw in JS. A JSError is
+// thrown as the original exception, and any other error as an Error with the
+// message of the error.
+type FunctionCallbackWithError func(info *FunctionCallbackInfo) (*Value, error)
+
 // FunctionCallbackInfo is the argument that is passed to a FunctionCallback.
 type FunctionCallbackInfo struct {
-	ctx  *Context
-	args []*Value
-	this *Object
+	ctx       *Context
+	args      []*Value
+	this      *Object
+	holder    *Object
+	newTarget *Value
+	data      *Value
 }
 
 // Context is the current context that the callback is being executed in.
@@ -28,21 +38,62 @@ func (i *FunctionCallbackInfo) Context() *Context {
 	return i.ctx
 }
 
+// Isolate is the isolate that the callback is being executed in.
+func (i *FunctionCallbackInfo) Isolate() *Isolate {
+	return i.ctx.iso
+}
+
 // This returns the receiver object "this".
 func (i *FunctionCallbackInfo) This() *Object {
 	return i.this
 }
 
+// Holder returns the object the function was found on, which differs from This
+// when the function is inherited through the prototype chain.
+func (i *FunctionCallbackInfo) Holder() *Object {
+	return i.holder
+}
+
 // Args returns a slice of the value arguments that are passed to the JS function.
 func (i *FunctionCallbackInfo) Args() []*Value {
 	return i.args
 }
 
+// Length returns the number of arguments that are passed to the JS function.
+func (i *FunctionCallbackInfo) Length() int {
+	return len(i.args)
+}
+
+// IsConstructCall returns true if the function is called with `new`.
+func (i *FunctionCallbackInfo) IsConstructCall() bool {
+	return !i.newTarget.IsUndefined()
+}
+
+// NewTarget returns `new.target`, the constructor `new` was called with, or
+// undefined if the function is not called as a constructor.
+func (i *FunctionCallbackInfo) NewTarget() *Value {
+	return i.newTarget
+}
+
+// Data returns the value given as FunctionTemplateOptions.Data to the template
+// of the function, or undefined.
+func (i *FunctionCallbackInfo) Data() *Value {
+	if i.data == nil {
+		return Undefined(i.ctx.iso)
+	}
+	return i.data
+}
+
 // FunctionTemplate is used to create functions at runtime.
 // There can only be one function created from a FunctionTemplate in a context.
 // The lifetime of the created function is equal to the lifetime of the context.
 type FunctionTemplate struct {
 	*template
+
+	// the instance and prototype templates are wrapped once, on first use
+	subTemplatesMutex sync.Mutex
+	instance          *ObjectTemplate
+	prototype         *ObjectTemplate
 }
 
 // NewFunctionTemplate creates a FunctionTemplate for a given callback.
@@ -56,15 +107,102 @@ func NewFunctionTemplate(iso *Isolate, callback FunctionCallback) *FunctionTempl
 
 	cbref := iso.registerCallback(callback)
 
+	var ptr C.TemplatePtr
+	if hasExtensions {
+		ptr = C.NewFunctionTemplateWithInfo(iso.ptr, C.int(cbref))
+	} else {
+		// without the holder and new.target, see callFunctionCallback
+		ptr = C.NewFunctionTemplate(iso.ptr, C.int(cbref))
+	}
 	tmpl := &template{
-		ptr:  C.NewFunctionTemplate(iso.ptr, C.int(cbref)),
+		ptr:  ptr,
 		iso:  iso,
 		Name: time.Now().String(),
 	}
 	iso.templateLock.Lock()
 	defer iso.templateLock.Unlock()
 	iso.templates = append(iso.templates, tmpl)
-	return &FunctionTemplate{tmpl}
+	return &FunctionTemplate{template: tmpl}
+}
+
+// ConstructorBehavior selects whether a function can be called with `new`.
+type ConstructorBehavior int
+
+const (
+	// ConstructorAllow lets the function be called both with and without `new`.
+	ConstructorAllow ConstructorBehavior = iota
+	// ConstructorThrow throws a TypeError when the function is called with
+	// `new`, like methods and arrow functions in JS.
+	ConstructorThrow
+	// ConstructorOnly throws a TypeError when the function is called without
+	// `new`, like classes in JS.
+	ConstructorOnly
+)
+
+// FunctionTemplateOptions configures NewFunctionTemplateWithOptions.
+type FunctionTemplateOptions struct {
+	// Name is the name of the function, empty for an anonymous function.
+	Name string
+	// Length is the value of the length property of the function, the number
+	// of arguments it expects.
+	Length int
+	// Behavior selects whether the function can be called with `new`.
+	Behavior ConstructorBehavior
+	// Data is passed to each call of the callback as FunctionCallbackInfo.Data.
+	Data *Value
+}
+
+// NewFunctionTemplateWithOptions creates a FunctionTemplate for a given
+// callback, with options to make the function behave like a JS builtin.
+func NewFunctionTemplateWithOptions(iso *Isolate, callback FunctionCallback, opts FunctionTemplateOptions) *FunctionTemplate {
+	if callback == nil {
+		panic("nil FunctionCallback argument not supported")
+	}
+	msg := "Constructor requires 'new'"
+	if opts.Name != "" {
+		msg = fmt.Sprintf("Constructor %s requires 'new'", opts.Name)
+	}
+	cb := callback
+	if opts.Data != nil || opts.Behavior == ConstructorOnly {
+		cb = func(info *FunctionCallbackInfo) *Value {
+			if opts.Behavior == ConstructorOnly && !info.IsConstructCall() {
+				return info.ctx.iso.ThrowException(newErrorValue(info.ctx.iso, errorKindTypeError, msg))
+			}
+			info.data = opts.Data
+			return callback(info)
+		}
+	}
+
+	tmpl := NewFunctionTemplate(iso, cb)
+	if opts.Name != "" {
+		tmpl.SetClassName(opts.Name)
+	}
+	if opts.Length != 0 {
+		C.FunctionTemplateSetLength(tmpl.ptr, C.int(opts.Length))
+	}
+	if opts.Behavior == ConstructorThrow {
+		tmpl.RemovePrototype()
+	}
+	return tmpl
+}
+
+// NewFunctionTemplateWithError creates a FunctionTemplate for a given callback
+// that can return an error to throw in JS.
+func NewFunctionTemplateWithError(iso *Isolate, callback FunctionCallbackWithError) *FunctionTemplate {
+	if callback == nil {
+		panic("nil FunctionCallbackWithError argument not supported")
+	}
+	return NewFunctionTemplate(iso, callback.toFunctionCallback())
+}
+
+func (cb FunctionCallbackWithError) toFunctionCallback() FunctionCallback {
+	return func(info *FunctionCallbackInfo) *Value {
+		val, err := cb(info)
+		if err != nil {
+			return info.ctx.iso.throwError(err)
+		}
+		return val
+	}
 }
 
 // GetFunction returns an instance of this function template bound to the given context.
@@ -77,29 +215,124 @@ func (tmpl *FunctionTemplate) GetFunction(ctx *Context) *Function {
 	return &Function{val}
 }
 
-// Note that ideally `thisAndArgs` would be split into two separate arguments, but they were combined
+// InstanceTemplate returns the template of the objects created when the
+// function is called as a constructor. Properties set on it are own
+// properties of each instance.
+func (tmpl *FunctionTemplate) InstanceTemplate() *ObjectTemplate {
+	tmpl.subTemplatesMutex.Lock()
+	defer tmpl.subTemplatesMutex.Unlock()
+	if tmpl.instance == nil {
+		tmpl.instance = &ObjectTemplate{newTemplate(tmpl.iso, C.FunctionTemplateInstanceTemplate(tmpl.ptr))}
+	}
+	return tmpl.instance
+}
+
+// PrototypeTemplate returns the template of the prototype object of the
+// function. Properties set on it, typically methods, are shared by all the
+// instances.
+func (tmpl *FunctionTemplate) PrototypeTemplate() *ObjectTemplate {
+	tmpl.subTemplatesMutex.Lock()
+	defer tmpl.subTemplatesMutex.Unlock()
+	if tmpl.prototype == nil {
+		tmpl.prototype = &ObjectTemplate{newTemplate(tmpl.iso, C.FunctionTemplatePrototypeTemplate(tmpl.ptr))}
+	}
+	return tmpl.prototype
+}
+
+// SetClassName sets the name of the function, and the constructor name of the
+// objects it constructs as shown in stack traces and DevTools.
+func (tmpl *FunctionTemplate) SetClassName(name string) {
+	vname, err := NewValue(tmpl.iso, name)
+	if err != nil {
+		panic(err)
+	}
+	C.FunctionTemplateSetClassName(tmpl.ptr, vname.ptr)
+}
+
+// Inherit makes the prototype of the function inherit from the prototype of
+// parent, so that instances are `instanceof` both functions and see the
+// methods of both prototype templates. It must be called before GetFunction
+// is called on either template.
+func (tmpl *FunctionTemplate) Inherit(parent *FunctionTemplate) {
+	if parent == nil {
+		panic("nil FunctionTemplate argument not supported")
+	}
+	C.FunctionTemplateInherit(tmpl.ptr, parent.ptr)
+}
+
+// ReadOnlyPrototype makes the prototype property of the function read-only,
+// like for classes in JS.
+func (tmpl *FunctionTemplate) ReadOnlyPrototype() {
+	C.FunctionTemplateReadOnlyPrototype(tmpl.ptr)
+}
+
+// RemovePrototype removes the prototype property of the function, which then
+// cannot be used as a constructor, like for methods and arrow functions in JS.
+func (tmpl *FunctionTemplate) RemovePrototype() {
+	C.FunctionTemplateRemovePrototype(tmpl.ptr)
+}
+
+// `thisAndArgs` holds this followed by the arguments, for the functions created by
+// the C side from a callback ref, such as the reactions of PromiseThen.
+// Note that ideally `thisAndArgs` would be split into separate arguments, but they were combined
 // to workaround an ERROR_COMMITMENT_LIMIT error on windows that was detected in CI.
 //export goFunctionCallback
 func goFunctionCallback(ctxref int, cbref int, thisAndArgs *C.ValuePtr, argsCount int) C.ValuePtr {
+	return callFunctionCallback(ctxref, cbref, thisAndArgs, argsCount, false)
+}
+
+// `thisAndArgs` holds this, holder and new.target followed by the arguments, for the
+// functions of FunctionTemplate and NewFunction.
+//export goFunctionCallbackInfo
+func goFunctionCallbackInfo(ctxref int, cbref int, thisAndArgs *C.ValuePtr, argsCount int) C.ValuePtr {
+	return callFunctionCallback(ctxref, cbref, thisAndArgs, argsCount, true)
+}
+
+// callFunctionCallback calls the callback with the values of thisAndArgs, which
+// include the holder and new.target when withInfo is true. Otherwise the holder
+// is this, and new.target is undefined. Calls in a closed context return undefined.
+// A panic in the callback is recovered and thrown in JS, as it cannot unwind through V8.
+func callFunctionCallback(ctxref int, cbref int, thisAndArgs *C.ValuePtr, argsCount int, withInfo bool) (rtn C.ValuePtr) {
 	ctx := getContext(ctxref)
+	if ctx == nil {
+		// the context was closed, so there is no isolate to call the callback in
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			rtn = ctx.iso.throwPanic(r).ptr
+		}
+	}()
 
-	this := *thisAndArgs
+	leading := 1
+	if withInfo {
+		leading = 3
+	}
+	values := (*[1 << 30]C.ValuePtr)(unsafe.Pointer(thisAndArgs))[: argsCount+leading : argsCount+leading]
 	info := &FunctionCallbackInfo{
 		ctx:  ctx,
-		this: &Object{Value: NewValueStruct(this, ctx.iso)},
+		this: &Object{Value: NewValueStruct(values[0], ctx.iso)},
 		args: make([]*Value, argsCount),
 	}
+	bt := make([]*Value, 0, argsCount+3)
+	bt = append(bt, info.this.Value)
+	if withInfo {
+		info.holder = &Object{Value: NewValueStruct(values[1], ctx.iso)}
+		info.newTarget = NewValueStruct(values[2], ctx.iso)
+		bt = append(bt, info.holder.Value, info.newTarget)
+	} else {
+		// the cached undefined of the isolate must not be released
+		info.holder = info.this
+		info.newTarget = Undefined(ctx.iso)
+	}
 	defer func() {
-		bt := make([]*Value, 0)
-		bt = append(bt, info.this.Value)
 		bt = append(bt, info.args...)
 		if TraceMem {
 			fmt.Println("Mark Can Be Released By Func Call This And Args,Len ", len(bt))
 		}
 		ctx.iso.BatchMarkCanReleaseInC(bt...)
 	}()
-	argv := (*[1 << 30]C.ValuePtr)(unsafe.Pointer(thisAndArgs))[1 : argsCount+1 : argsCount+1]
-	for i, v := range argv {
+	for i, v := range values[leading:] {
 		val := NewValueStruct(v, ctx.iso)
 		info.args[i] = val
 	}
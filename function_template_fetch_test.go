@@ -22,34 +22,48 @@ import (
 func ExampleFunctionTemplate_fetch() {
 	iso := v8.NewIsolate()
 	defer iso.Dispose()
-	global := v8.NewObjectTemplate(iso)
 
-	fetchfn := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
-		args := info.Args()
-		url := args[0].String()
+	var ctx *v8.Context
+	var prom *v8.Promise
+	iso.Run(func() {
+		global := v8.NewObjectTemplate(iso)
 
-		resolver, _ := v8.NewPromiseResolver(info.Context())
+		fetchfn := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+			args := info.Args()
+			url := args[0].String()
 
-		go func() {
-			res, _ := http.Get(url)
-			body, _ := ioutil.ReadAll(res.Body)
-			val, _ := v8.NewValue(iso, string(body))
-			resolver.Resolve(val)
-		}()
-		return resolver.GetPromise().Value
-	})
-	global.Set("fetch", fetchfn, v8.ReadOnly)
+			resolver, _ := v8.NewPromiseResolver(info.Context())
+
+			go func() {
+				res, _ := http.Get(url)
+				body, _ := ioutil.ReadAll(res.Body)
+				// the isolate must only be used from its executor, not from this goroutine
+				iso.Post(func() {
+					val, _ := v8.NewValue(iso, string(body))
+					resolver.Resolve(val)
+				})
+			}()
+			return resolver.GetPromise().Value
+		})
+		global.Set("fetch", fetchfn, v8.ReadOnly)
 
-	ctx := v8.NewContextWithOptions(iso, global)
-	defer ctx.Close()
-	val, _ := ctx.RunScript("fetch('https://rogchap.com/v8go')", "")
-	prom, _ := val.AsPromise()
+		ctx = v8.NewContextWithOptions(iso, global)
+		val, _ := ctx.RunScript("fetch('https://rogchap.com/v8go')", "")
+		prom, _ = val.AsPromise()
+	})
+	defer iso.Run(ctx.Close)
 
 	// wait for the promise to resolve
-	for prom.State() == v8.Pending {
-		continue
+	for {
+		var state v8.PromiseState
+		iso.Run(func() { state = prom.State() })
+		if state != v8.Pending {
+			break
+		}
 	}
-	fmt.Printf("%s\n", strings.Split(prom.Result().String(), "\n")[0])
+	var result string
+	iso.Run(func() { result = prom.Result().String() })
+	fmt.Printf("%s\n", strings.Split(result, "\n")[0])
 	// Output:
 	// <!DOCTYPE html>
 }
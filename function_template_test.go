@@ -5,7 +5,10 @@
 package v8go_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	v8 "gitee.com/hasika/v8go"
@@ -109,6 +112,243 @@ func TestFunctionCallbackInfoThis(t *testing.T) {
 	}
 }
 
+func TestFunctionTemplateInherit(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	noop := func(info *v8.FunctionCallbackInfo) *v8.Value { return nil }
+	entity := v8.NewFunctionTemplate(iso, noop)
+	entity.SetClassName("Entity")
+	entity.PrototypeTemplate().Set("kind", "entity")
+	id := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, "id")
+		return val
+	})
+	id.RemovePrototype()
+	entity.PrototypeTemplate().Set("id", id)
+
+	actor := v8.NewFunctionTemplate(iso, noop)
+	actor.SetClassName("Actor")
+	actor.Inherit(entity)
+	actor.InstanceTemplate().Set("hp", int32(100))
+	// the templates are wrapped once, however often they are requested
+	if actor.InstanceTemplate() != actor.InstanceTemplate() || entity.PrototypeTemplate() != entity.PrototypeTemplate() {
+		t.Error("expected the same template wrappers on each call")
+	}
+
+	player := v8.NewFunctionTemplate(iso, noop)
+	player.SetClassName("Player")
+	player.Inherit(actor)
+	player.ReadOnlyPrototype()
+
+	global := v8.NewObjectTemplate(iso)
+	global.Set("Entity", entity)
+	global.Set("Actor", actor)
+	global.Set("Player", player)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source   string
+		expected string
+	}{
+		{"const p = new Player(); [p instanceof Player, p instanceof Actor, p instanceof Entity].join(',')", "true,true,true"},
+		{"new Actor() instanceof Player", "false"},
+		{"p.constructor.name + ':' + Player.name", "Player:Player"},
+		{"Object.prototype.toString.call(p)", "[object Player]"},
+		{"p.kind + ':' + p.id()", "entity:id"},
+		{"const a = new Actor(); a.hasOwnProperty('hp') + ':' + a.hp", "true:100"},
+		{"Player.prototype = {}; Object.getPrototypeOf(p) === Player.prototype", "true"},
+		{"'prototype' in p.id", "false"},
+		{"try { new p.id() } catch (e) { e instanceof TypeError }", "true"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "inherit.js")
+		fatalIf(t, err)
+		if val.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.expected, val.String())
+		}
+	}
+}
+
+func TestFunctionTemplateWithError(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	global := v8.NewObjectTemplate(iso)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	fail := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return nil, errors.New("file not found")
+	})
+	call := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		fn, err := info.Args()[0].AsFunction()
+		if err != nil {
+			return nil, err
+		}
+		return fn.Call(v8.Undefined(iso))
+	})
+	ok := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return v8.NewValue(iso, "ok")
+	})
+	fatalIf(t, ctx.Global().Set("fail", fail.GetFunction(ctx)))
+	fatalIf(t, ctx.Global().Set("call", call.GetFunction(ctx)))
+	throwValue := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		val, err := v8.NewValue(iso, int32(42))
+		if err != nil {
+			return nil, err
+		}
+		return nil, &v8.JSException{Value: val}
+	})
+	fatalIf(t, ctx.Global().Set("ok", ok.GetFunction(ctx)))
+	fatalIf(t, ctx.Global().Set("throwValue", throwValue.GetFunction(ctx)))
+
+	tests := [...]struct {
+		source   string
+		expected string
+	}{
+		{"try { fail() } catch (e) { (e instanceof Error) + ':' + e.message }", "true:file not found"},
+		{"const thrown = { code: 42 }; try { call(() => { throw thrown }) } catch (e) { e === thrown }", "true"},
+		{"try { call(() => { throw new RangeError('bad') }) } catch (e) { e instanceof RangeError }", "true"},
+		{"ok()", "ok"},
+		{"try { throwValue() } catch (e) { e === 42 }", "true"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "error.js")
+		fatalIf(t, err)
+		if val.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.expected, val.String())
+		}
+	}
+
+	_, err := ctx.RunScript("fail()", "uncaught.js")
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) || !strings.Contains(jsErr.Message, "file not found") {
+		t.Errorf("expected JSError, got %v", err)
+	}
+	if exc := iso.Exception(err); exc == nil || exc.Err != jsErr || !exc.Value.IsNativeError() {
+		t.Error("expected the thrown Error to be preserved")
+	}
+}
+
+func TestFunctionCallbackPanic(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	global := v8.NewObjectTemplate(iso)
+	global.Set("boom", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	}))
+	global.Set("first", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		return info.Args()[0]
+	}))
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("try { boom() } catch (e) { e.message + '\\n' + e.stack }", "panic.js")
+	fatalIf(t, err)
+	if s := val.String(); !strings.Contains(s, "assignment to entry in nil map") || !strings.Contains(s, "Go stack:") {
+		t.Errorf("expected the panic and Go stack in the error, got %q", s)
+	}
+	if _, err := ctx.RunScript("first()", "index.js"); err == nil {
+		t.Error("expected an index out of range panic to be thrown in JS")
+	}
+	val, err = ctx.RunScript("'still running'", "after.js")
+	fatalIf(t, err)
+	if val.String() != "still running" {
+		t.Errorf("expected the isolate to be usable after a panic, got %s", val.String())
+	}
+}
+
+func TestFunctionCallbackInfo(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	type call struct {
+		construct, newTarget, data, holder bool
+		length                             int
+	}
+	var calls []call
+	fn := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		if info.Isolate() != iso {
+			t.Error("expected the isolate of the context")
+		}
+		calls = append(calls, call{
+			construct: info.IsConstructCall(),
+			newTarget: info.NewTarget().IsFunction(),
+			data:      info.Data().IsUndefined(),
+			holder:    info.Holder() != nil && info.Holder().IsObject(),
+			length:    info.Length(),
+		})
+		return nil
+	})
+	global := v8.NewObjectTemplate(iso)
+	global.Set("fn", fn)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	_, err := ctx.RunScript("fn(1, 2); new fn()", "info.js")
+	fatalIf(t, err)
+	expected := []call{
+		{construct: false, newTarget: false, data: true, holder: true, length: 2},
+		{construct: true, newTarget: true, data: true, holder: true, length: 0},
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %+v, got %+v", expected, calls)
+	}
+}
+
+func TestNewFunctionTemplateWithOptions(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	data, _ := v8.NewValue(iso, "shared")
+	echo := func(info *v8.FunctionCallbackInfo) *v8.Value {
+		return info.Data()
+	}
+	global := v8.NewObjectTemplate(iso)
+	global.Set("method", v8.NewFunctionTemplateWithOptions(iso, echo, v8.FunctionTemplateOptions{
+		Name:     "method",
+		Length:   2,
+		Behavior: v8.ConstructorThrow,
+		Data:     data,
+	}))
+	global.Set("Point", v8.NewFunctionTemplateWithOptions(iso, echo, v8.FunctionTemplateOptions{
+		Name:     "Point",
+		Behavior: v8.ConstructorOnly,
+	}))
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source   string
+		expected string
+	}{
+		{"method.name + ':' + method.length + ':' + method()", "method:2:shared"},
+		{"try { new method() } catch (e) { e instanceof TypeError }", "true"},
+		{"new Point() instanceof Point", "true"},
+		{"try { Point() } catch (e) { e.message }", "Constructor Point requires 'new'"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "options.js")
+		fatalIf(t, err)
+		if val.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.expected, val.String())
+		}
+	}
+}
+
 func ExampleFunctionTemplate() {
 	iso := v8.NewIsolate()
 	defer iso.Dispose()
@@ -5,7 +5,10 @@
 package v8go_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -35,6 +38,65 @@ func TestFunctionCall(t *testing.T) {
 	}
 }
 
+func TestNewFunction(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	echo := func(info *v8.FunctionCallbackInfo) *v8.Value {
+		return info.Args()[0]
+	}
+	if _, err := v8.NewFunction(nil, echo); err == nil {
+		t.Error("expected an error for a nil Context")
+	}
+	if _, err := v8.NewFunction(ctx, nil); err == nil {
+		t.Error("expected an error for a nil callback")
+	}
+
+	count := iso.CallbackCount()
+	fn, err := v8.NewFunction(ctx, echo)
+	fatalIf(t, err)
+	if iso.CallbackCount() != count+1 {
+		t.Fatalf("expected %d callbacks, got %d", count+1, iso.CallbackCount())
+	}
+	arg, err := v8.NewValue(iso, "echo")
+	fatalIf(t, err)
+	val, err := fn.Call(v8.Undefined(iso), arg)
+	fatalIf(t, err)
+	if val.String() != "echo" {
+		t.Errorf("expected %q, got %q", "echo", val.String())
+	}
+
+	iso.BatchMarkCanReleaseInC(fn.Value, arg, val)
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	if iso.CallbackCount() != count {
+		t.Errorf("expected the callback to be released, got %d callbacks", iso.CallbackCount())
+	}
+}
+
+func TestFunctionCallContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("(function loop() { while (true) {} })", "loop.js")
+	fatalIf(t, err)
+	fn, err := val.AsFunction()
+	fatalIf(t, err)
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := fn.CallContext(deadline, v8.Undefined(iso)); !errors.Is(err, v8.ErrDeadlineExceeded) {
+		t.Fatalf("expected ErrDeadlineExceeded, got %v", err)
+	}
+}
+
 func TestFunctionCallToGoFunc(t *testing.T) {
 	t.Parallel()
 
@@ -148,40 +148,43 @@ func (t *TsEnv) initGlobalClass() {
 		t.Log(info.Args())
 		return nil
 	}))
-	_ = globalClass.Set("__tgjsEvalScript", v8go.NewFunctionTemplate(t.Iso, func(info *v8go.FunctionCallbackInfo) *v8go.Value {
+	_ = globalClass.Set("__tgjsEvalScript", v8go.NewFunctionTemplateWithError(t.Iso, func(info *v8go.FunctionCallbackInfo) (*v8go.Value, error) {
 		source := info.Args()[0].String()
 		orig := info.Args()[1].String()
 		ret, err := t.RunScriptWithWrapperByScript(orig, nil, source, true)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		defer func() {
 			if ret != nil {
 				ret.MarkValuePtrCanReleaseInC()
 			}
 		}()
-		return ret
+		return ret, nil
 	}))
-	_ = globalClass.Set("__tgjsLoadModule", v8go.NewFunctionTemplate(t.Iso, func(info *v8go.FunctionCallbackInfo) *v8go.Value {
+	_ = globalClass.Set("__tgjsLoadModule", v8go.NewFunctionTemplateWithError(t.Iso, func(info *v8go.FunctionCallbackInfo) (*v8go.Value, error) {
 		moduleName := info.Args()[0].String()
 		requireDir := info.Args()[1].String()
 		path := requireDir + "/" + moduleName + ".js"
 		path, err := GetAbsPath(path)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		bs, err := t.loadFile(path)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		ret := fmt.Sprintf("%s\n%s\n%s", path, path, string(bs))
-		retV, _ := v8go.NewValue(t.Iso, ret)
+		retV, err := v8go.NewValue(t.Iso, ret)
+		if err != nil {
+			return nil, err
+		}
 		defer func() {
 			if retV != nil {
 				retV.MarkValuePtrCanReleaseInC()
 			}
 		}()
-		return retV
+		return retV, nil
 	}))
 }
 
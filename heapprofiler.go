@@ -0,0 +1,140 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include "v8go.h"
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"time"
+	"unsafe"
+)
+
+const (
+	// DefaultHeapSamplingInterval is the average number of bytes between
+	// samples used by StartSampling when the interval is 0.
+	DefaultHeapSamplingInterval = 512 * 1024
+
+	// DefaultHeapSamplingStackDepth is the maximum stack depth recorded by
+	// StartSampling when the depth is 0.
+	DefaultHeapSamplingStackDepth = 16
+)
+
+// HeapProfiler is used to control sampling heap profiling.
+type HeapProfiler struct {
+	p   *C.HeapProfiler
+	iso *Isolate
+
+	samplingInterval uint64
+}
+
+// NewHeapProfiler creates a HeapProfiler for the isolate.
+func NewHeapProfiler(iso *Isolate) *HeapProfiler {
+	profiler := C.NewHeapProfiler(iso.ptr)
+	return &HeapProfiler{
+		p:   profiler,
+		iso: iso,
+	}
+}
+
+// Dispose will dispose the profiler, stopping sampling if it was started.
+func (h *HeapProfiler) Dispose() {
+	if h.p == nil {
+		return
+	}
+
+	C.HeapProfilerDispose(h.p)
+	h.p = nil
+}
+
+// StartSampling starts collecting a sampling heap profile. On average an
+// allocation is sampled every interval bytes, and up to depth frames of its
+// stack are recorded; 0 selects DefaultHeapSamplingInterval and
+// DefaultHeapSamplingStackDepth. Returns false if sampling was already started.
+func (h *HeapProfiler) StartSampling(interval uint64, depth int) bool {
+	if h.p == nil || h.iso.ptr == nil {
+		panic("profiler or isolate are nil")
+	}
+	if interval == 0 {
+		interval = DefaultHeapSamplingInterval
+	}
+	if depth <= 0 {
+		depth = DefaultHeapSamplingStackDepth
+	}
+
+	if C.HeapProfilerStartSamplingHeapProfiler(h.p, C.uint64_t(interval), C.int(depth)) == 0 {
+		return false
+	}
+	h.samplingInterval = interval
+	return true
+}
+
+// StopSampling stops collecting the sampling heap profile and discards the
+// samples collected so far.
+func (h *HeapProfiler) StopSampling() {
+	if h.p == nil || h.iso.ptr == nil {
+		panic("profiler or isolate are nil")
+	}
+
+	C.HeapProfilerStopSamplingHeapProfiler(h.p)
+}
+
+// GetAllocationProfile returns the allocations sampled since StartSampling
+// that are still alive, or nil if sampling is not started.
+func (h *HeapProfiler) GetAllocationProfile() *AllocationProfile {
+	if h.p == nil || h.iso.ptr == nil {
+		panic("profiler or isolate are nil")
+	}
+
+	profile := C.HeapProfilerGetAllocationProfile(h.p)
+	if profile == nil {
+		return nil
+	}
+	defer C.AllocationProfileDelete(profile)
+
+	return &AllocationProfile{
+		root:             newAllocationProfileNode(profile.root, nil),
+		samplingInterval: h.samplingInterval,
+		time:             time.Now(),
+	}
+}
+
+// WriteProfile writes the current allocation profile to w as a gzipped pprof
+// protocol buffer, to be read with `go tool pprof`.
+func (h *HeapProfiler) WriteProfile(w io.Writer) error {
+	profile := h.GetAllocationProfile()
+	if profile == nil {
+		return errors.New("v8go: heap sampling has not been started")
+	}
+	return profile.WritePprof(w)
+}
+
+func newAllocationProfileNode(node *C.AllocationProfileNode, parent *AllocationProfileNode) *AllocationProfileNode {
+	n := &AllocationProfileNode{
+		scriptResourceName: C.GoString(node.scriptResourceName),
+		functionName:       C.GoString(node.functionName),
+		scriptID:           int(node.scriptId),
+		lineNumber:         int(node.lineNumber),
+		columnNumber:       int(node.columnNumber),
+		parent:             parent,
+	}
+
+	if node.allocationsCount > 0 {
+		for _, a := range (*[1 << 28]C.AllocationProfileAllocation)(unsafe.Pointer(node.allocations))[:node.allocationsCount:node.allocationsCount] {
+			n.allocations = append(n.allocations, Allocation{Size: uint64(a.size), Count: uint64(a.count)})
+		}
+	}
+
+	if node.childrenCount > 0 {
+		for _, child := range (*[1 << 28]*C.AllocationProfileNode)(unsafe.Pointer(node.children))[:node.childrenCount:node.childrenCount] {
+			n.children = append(n.children, newAllocationProfileNode(child, n))
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestHeapProfiler_Dispose(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	heapProfiler := v8.NewHeapProfiler(iso)
+
+	heapProfiler.Dispose()
+	// noop when called multiple times
+	heapProfiler.Dispose()
+
+	// verify panics when profiler disposed
+	if recoverPanic(func() { heapProfiler.StartSampling(0, 0) }) == nil {
+		t.Error("expected panic")
+	}
+
+	if recoverPanic(func() { heapProfiler.GetAllocationProfile() }) == nil {
+		t.Error("expected panic")
+	}
+}
+
+func TestHeapProfiler(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	heapProfiler := v8.NewHeapProfiler(iso)
+	defer heapProfiler.Dispose()
+
+	if profile := heapProfiler.GetAllocationProfile(); profile != nil {
+		t.Error("expected no profile before sampling is started")
+	}
+	if err := heapProfiler.WriteProfile(&bytes.Buffer{}); err == nil {
+		t.Error("expected error before sampling is started")
+	}
+
+	if !heapProfiler.StartSampling(128, 0) {
+		t.Fatal("expected sampling to start")
+	}
+	defer heapProfiler.StopSampling()
+
+	_, err := ctx.RunScript(allocationScript, "alloc.js")
+	fatalIf(t, err)
+
+	profile := heapProfiler.GetAllocationProfile()
+	if profile == nil {
+		t.Fatal("expected profile")
+	}
+	root := profile.GetRoot()
+	if root.GetParent() != nil {
+		t.Error("expected root to have no parent")
+	}
+	if root.GetTotalSize() == 0 {
+		t.Error("expected sampled allocations")
+	}
+
+	var found bool
+	var walk func(n *v8.AllocationProfileNode)
+	walk = func(n *v8.AllocationProfileNode) {
+		if n.GetFunctionName() == "allocate" && len(n.GetAllocations()) > 0 {
+			found = true
+			if n.GetScriptResourceName() != "alloc.js" {
+				t.Errorf("expected alloc.js, got %q", n.GetScriptResourceName())
+			}
+			if n.GetLineNumber() != 1 {
+				t.Errorf("expected line 1, got %d", n.GetLineNumber())
+			}
+		}
+		for i := 0; i < n.GetChildrenCount(); i++ {
+			walk(n.GetChild(i))
+		}
+	}
+	walk(root)
+	if !found {
+		t.Error("expected allocations to be attributed to allocate")
+	}
+
+	var buf bytes.Buffer
+	fatalIf(t, heapProfiler.WriteProfile(&buf))
+	if buf.Len() == 0 {
+		t.Error("expected profile to be written")
+	}
+}
+
+const allocationScript = `function allocate(n) {
+  var out = [];
+  for (var i = 0; i < n; i++) out.push({ index: i, name: 'object ' + i });
+  return out;
+}
+var retained = allocate(10000);`
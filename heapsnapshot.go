@@ -0,0 +1,112 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// HeapSnapshotOptions configures TakeHeapSnapshotWithOptions.
+type HeapSnapshotOptions struct {
+	// ExposeGoRoots adds every value still held by Go (see GetTracedValueCnt)
+	// to the snapshot as a child of a "(Go roots)" node, so that objects
+	// retained from Go show up as such rather than as anonymous globals.
+	ExposeGoRoots bool
+
+	// NamedRoots adds the given values to the "(Go roots)" node under the
+	// given names, which makes it easy to find them in DevTools.
+	NamedRoots map[string]Valuer
+}
+
+type heapSnapshotWriter struct {
+	w   io.Writer
+	err error
+}
+
+var heapSnapshotMutex sync.Mutex
+var heapSnapshotRegistry = make(map[int]*heapSnapshotWriter)
+var heapSnapshotSeq = 0
+
+// TakeHeapSnapshot takes a snapshot of the ISO heap and streams it to w in the
+// DevTools .heapsnapshot JSON format.
+func (i *Isolate) TakeHeapSnapshot(w io.Writer) error {
+	return i.TakeHeapSnapshotWithOptions(w, HeapSnapshotOptions{})
+}
+
+// TakeHeapSnapshotWithOptions is like TakeHeapSnapshot, with options to
+// expose the values held by Go as named roots.
+func (i *Isolate) TakeHeapSnapshotWithOptions(w io.Writer, opts HeapSnapshotOptions) error {
+	if i.ptr == nil {
+		return errors.New("v8go: Isolate has been disposed")
+	}
+
+	var roots []C.ValuePtr
+	var names []*C.char
+	for name, val := range opts.NamedRoots {
+		cname := C.CString(name)
+		defer FreeCPtr(unsafe.Pointer(cname))
+		roots = append(roots, val.value().ptr)
+		names = append(names, cname)
+	}
+	if opts.ExposeGoRoots {
+		goValue := C.CString("Go value")
+		defer FreeCPtr(unsafe.Pointer(goValue))
+		i.tracedValuePtrLock.Lock()
+		for ptr := range i.tracedValuePtrMap {
+			roots = append(roots, ptr)
+			names = append(names, goValue)
+		}
+		i.tracedValuePtrLock.Unlock()
+	}
+
+	var rootsPtr *C.ValuePtr
+	var namesPtr **C.char
+	if len(roots) > 0 {
+		rootsPtr = &roots[0]
+		namesPtr = &names[0]
+	}
+
+	sw := &heapSnapshotWriter{w: w}
+	heapSnapshotMutex.Lock()
+	heapSnapshotSeq++
+	ref := heapSnapshotSeq
+	heapSnapshotRegistry[ref] = sw
+	heapSnapshotMutex.Unlock()
+	defer func() {
+		heapSnapshotMutex.Lock()
+		delete(heapSnapshotRegistry, ref)
+		heapSnapshotMutex.Unlock()
+	}()
+
+	ok := C.IsolateTakeHeapSnapshot(i.ptr, C.int(ref), rootsPtr, namesPtr, C.int(len(roots)))
+	if sw.err != nil {
+		return sw.err
+	}
+	if ok == 0 {
+		return errors.New("v8go: heap snapshot serialization was aborted")
+	}
+	return nil
+}
+
+//export goHeapSnapshotWrite
+func goHeapSnapshotWrite(ref int, data *C.char, size C.int) C.int {
+	heapSnapshotMutex.Lock()
+	sw := heapSnapshotRegistry[ref]
+	heapSnapshotMutex.Unlock()
+	if sw == nil || sw.err != nil {
+		return 0
+	}
+	if _, err := sw.w.Write(C.GoBytes(unsafe.Pointer(data), size)); err != nil {
+		sw.err = err
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+type heapSnapshot struct {
+	Snapshot struct {
+		NodeCount int `json:"node_count"`
+	} `json:"snapshot"`
+	Nodes   []int    `json:"nodes"`
+	Strings []string `json:"strings"`
+}
+
+func TestIsolateTakeHeapSnapshot(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	_, err := ctx.RunScript("class Retained {}; globalThis.retained = new Retained()", "main.js")
+	fatalIf(t, err)
+
+	var buf bytes.Buffer
+	fatalIf(t, iso.TakeHeapSnapshot(&buf))
+
+	var snapshot heapSnapshot
+	fatalIf(t, json.Unmarshal(buf.Bytes(), &snapshot))
+	if snapshot.Snapshot.NodeCount == 0 || len(snapshot.Nodes) == 0 {
+		t.Fatal("expected snapshot to contain nodes")
+	}
+	if !containsString(snapshot.Strings, "Retained") {
+		t.Error("expected snapshot to contain the Retained class")
+	}
+}
+
+func TestIsolateTakeHeapSnapshot_GoRoots(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	held, err := ctx.RunScript("({ heldFromGo: true })", "main.js")
+	fatalIf(t, err)
+
+	var buf bytes.Buffer
+	fatalIf(t, iso.TakeHeapSnapshotWithOptions(&buf, v8.HeapSnapshotOptions{
+		ExposeGoRoots: true,
+		NamedRoots:    map[string]v8.Valuer{"session state": held},
+	}))
+
+	var snapshot heapSnapshot
+	fatalIf(t, json.Unmarshal(buf.Bytes(), &snapshot))
+	for _, s := range []string{"(Go roots)", "session state", "Go value"} {
+		if !containsString(snapshot.Strings, s) {
+			t.Errorf("expected snapshot to contain %q", s)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestIsolateTakeHeapSnapshot_WriteError(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if err := iso.TakeHeapSnapshot(failingWriter{}); err == nil || err.Error() != "disk full" {
+		t.Errorf("expected write error, got %v", err)
+	}
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
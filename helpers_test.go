@@ -1,6 +1,16 @@
 package v8go_test
 
-import "testing"
+import (
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+// setTestFlags sets the V8 flags the tests rely on. Flags are process-global,
+// so they are set once from TestMain rather than from a parallel test.
+func setTestFlags() {
+	v8.SetFlags("--expose-gc")
+}
 
 func fatalIf(t *testing.T, err error) {
 	t.Helper()
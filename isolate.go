@@ -9,9 +9,11 @@ package v8go
 import "C"
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -22,10 +24,12 @@ var v8once sync.Once
 // with many V8 contexts for execution.
 type Isolate struct {
 	ptr C.IsolatePtr
+	ref int
 
 	cbMutex sync.RWMutex
 	cbSeq   int
 	cbs     map[int]FunctionCallback
+	props   map[int]*propertyHandler
 
 	null      *Value
 	undefined *Value
@@ -45,6 +49,18 @@ type Isolate struct {
 
 	stopLock sync.Mutex
 	stopped  bool
+
+	nearHeapLimitCallback NearHeapLimitCallback
+
+	terminationLock   sync.Mutex
+	terminationReason error
+
+	executorLock sync.Mutex
+	executor     *executor
+
+	// exceptions holds the values thrown for the JSErrors still referenced
+	exceptionsLock sync.Mutex
+	exceptions     map[*JSError]*Value
 }
 
 func (i *Isolate) TraceScriptPtr(ptr C.UnboundScriptPtr) {
@@ -167,6 +183,56 @@ type HeapStatistics struct {
 // An *Isolate can be used as a v8go.ContextOption to create a new
 // Context, rather than creating a new default Isolate.
 func NewIsolate() *Isolate {
+	return newIsolate(func(ref int) C.IsolatePtr {
+		return C.NewIsolate(C.int(ref))
+	})
+}
+
+// IsolateOptions sets the resource constraints of an Isolate created with
+// NewIsolateWithOptions. Zero values keep the V8 defaults.
+type IsolateOptions struct {
+	// MaxOldGenerationSize is the maximum size in bytes of the old generation heap.
+	MaxOldGenerationSize uint64
+
+	// MaxYoungGenerationSize is the maximum size in bytes of the young generation heap.
+	MaxYoungGenerationSize uint64
+
+	// InitialHeapSize is the size in bytes the old generation heap starts with.
+	InitialHeapSize uint64
+
+	// StackLimit is the maximum size in bytes of the stack used by JavaScript
+	// execution, measured from the thread the ISO is created on.
+	StackLimit uint64
+
+	// NearHeapLimitCallback is called when the heap gets close to its limit.
+	// When nil, execution is terminated as soon as the limit is reached.
+	NearHeapLimitCallback NearHeapLimitCallback
+}
+
+// NearHeapLimitCallback is called when the ISO heap is close to currentHeapLimit;
+// initialHeapLimit is the limit the ISO was created with. Returning a larger
+// limit lets execution continue, returning currentHeapLimit (or less) terminates
+// the running script, which then fails with ErrHeapLimitExceeded.
+type NearHeapLimitCallback func(iso *Isolate, currentHeapLimit, initialHeapLimit uint64) uint64
+
+// NewIsolateWithOptions creates a new V8 ISO with the given resource constraints.
+// Unlike NewIsolate, running out of heap terminates the running script with
+// ErrHeapLimitExceeded instead of aborting the process.
+func NewIsolateWithOptions(opts IsolateOptions) *Isolate {
+	cOptions := C.IsolateOptions{
+		max_old_generation_size_in_bytes:     C.size_t(opts.MaxOldGenerationSize),
+		max_young_generation_size_in_bytes:   C.size_t(opts.MaxYoungGenerationSize),
+		initial_old_generation_size_in_bytes: C.size_t(opts.InitialHeapSize),
+		stack_size_in_bytes:                  C.size_t(opts.StackLimit),
+	}
+	iso := newIsolate(func(ref int) C.IsolatePtr {
+		return C.NewIsolateWithOptions(C.int(ref), cOptions)
+	})
+	iso.nearHeapLimitCallback = opts.NearHeapLimitCallback
+	return iso
+}
+
+func newIsolate(create func(ref int) C.IsolatePtr) *Isolate {
 	v8once.Do(func() {
 		C.Init()
 		C.InitV8GoCallBack()
@@ -177,8 +243,10 @@ func NewIsolate() *Isolate {
 	ref = ctxSeq
 	ctxMutex.Unlock()
 	iso := &Isolate{
-		ptr:                       C.NewIsolate(C.int(ref)),
+		ptr:                       create(ref),
+		ref:                       ref,
 		cbs:                       make(map[int]FunctionCallback),
+		props:                     make(map[int]*propertyHandler),
 		tracedValuePtrMap:         map[C.ValuePtr]interface{}{},
 		canReleasedValuePtrMap:    map[C.ValuePtr]interface{}{},
 		tracedUnboundScriptPtrMap: map[C.UnboundScriptPtr]interface{}{},
@@ -201,6 +269,121 @@ func (i *Isolate) TerminateExecution() {
 	C.IsolateTerminateExecution(i.ptr)
 }
 
+// CancelTerminateExecution resumes execution capability in the ISO after a
+// call to TerminateExecution, so that scripts can be run again.
+func (i *Isolate) CancelTerminateExecution() {
+	C.IsolateCancelTerminateExecution(i.ptr)
+}
+
+// runContext runs fn, terminating its JavaScript execution if ctx is done before
+// fn returns. Once fn has returned any pending termination is canceled so that
+// the ISO can keep running scripts.
+func (i *Isolate) runContext(ctx context.Context, fn func() (*Value, error)) (*Value, error) {
+	if ctx.Done() == nil {
+		return fn()
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+
+	done := make(chan struct{})
+	terminated := make(chan bool, 1)
+	// reason is only read once terminated has been received
+	var reason error
+	var setReason bool
+	go func() {
+		select {
+		case <-ctx.Done():
+			reason = contextError(ctx)
+			setReason = i.terminateWithReason(reason)
+			terminated <- true
+		case <-done:
+			terminated <- false
+		}
+	}()
+
+	val, err := fn()
+	close(done)
+	if <-terminated {
+		// The watchdog may have fired after fn returned, in which case the reason
+		// was not reported and the termination would hit the next script. Only
+		// the reason set here is cleared, not one such as ErrHeapLimitExceeded.
+		i.terminationLock.Lock()
+		if setReason && i.terminationReason == reason {
+			i.terminationReason = nil
+		}
+		i.terminationLock.Unlock()
+		i.CancelTerminateExecution()
+	}
+	return val, err
+}
+
+// contextError returns the error reported for a done ctx: ErrDeadlineExceeded
+// or ErrExecutionTerminated, along with the cause ctx was canceled with, if any.
+func contextError(ctx context.Context) error {
+	err := ErrExecutionTerminated
+	if ctx.Err() == context.DeadlineExceeded {
+		err = ErrDeadlineExceeded
+	}
+	if cause := contextCause(ctx); cause != nil && cause != ctx.Err() {
+		return &contextCauseError{err: err, cause: cause}
+	}
+	return err
+}
+
+// terminateWithReason terminates the current JavaScript execution and records
+// reason as the error to report once the termination has unwound, unless an
+// earlier reason is still pending. It reports whether reason was recorded.
+func (i *Isolate) terminateWithReason(reason error) bool {
+	i.terminationLock.Lock()
+	set := i.terminationReason == nil
+	if set {
+		i.terminationReason = reason
+	}
+	i.terminationLock.Unlock()
+	C.IsolateTerminateExecution(i.ptr)
+	return set
+}
+
+// executionError returns the reason recorded by terminateWithReason in place of
+// the termination exception err. The reason is only reported once no JavaScript
+// frames are left on the stack, so calls nested in a FunctionCallback still see
+// the termination exception and the outermost call reports the reason.
+func (i *Isolate) executionError(err error) error {
+	if i == nil {
+		return err
+	}
+	i.terminationLock.Lock()
+	defer i.terminationLock.Unlock()
+	if i.terminationReason == nil || i.IsExecutionTerminating() {
+		return err
+	}
+	reason := i.terminationReason
+	i.terminationReason = nil
+	return reason
+}
+
+//export goNearHeapLimitCallback
+func goNearHeapLimitCallback(ref int, currentHeapLimit C.size_t, initialHeapLimit C.size_t) C.size_t {
+	ctx := getContext(ref)
+	if ctx == nil {
+		return currentHeapLimit
+	}
+	iso := ctx.iso
+	if cb := iso.nearHeapLimitCallback; cb != nil {
+		limit := cb(iso, uint64(currentHeapLimit), uint64(initialHeapLimit))
+		if limit > uint64(currentHeapLimit) {
+			return C.size_t(limit)
+		}
+	}
+	iso.terminateWithReason(ErrHeapLimitExceeded)
+	// The termination only unwinds at the next interrupt, and V8 aborts the
+	// process if it runs out of heap before then, so the limit is raised to
+	// leave room for it. The initial limit is restored once the heap shrinks,
+	// see NewIsolateWithOptions in v8_export.h.
+	return currentHeapLimit + initialHeapLimit/4
+}
+
 // IsExecutionTerminating returns whether V8 is currently terminating
 // Javascript execution. If true, there are still JavaScript frames
 // on the stack and the termination exception is still active.
@@ -247,7 +430,7 @@ func (i *Isolate) CompileUnboundScript(source, origin string, opts CompileOption
 
 	rtn := C.IsolateCompileUnboundScript(i.ptr, cSource, cOrigin, cOptions)
 	if rtn.ptr == nil {
-		return nil, newJSError(rtn.error)
+		return nil, newJSError(i, rtn.error)
 	}
 	if opts.CachedData != nil {
 		opts.CachedData.Rejected = int(rtn.cachedDataRejected) == 1
@@ -278,8 +461,75 @@ func (i *Isolate) GetHeapStatistics() HeapStatistics {
 	}
 }
 
+// MemoryPressureLevel is the level of memory pressure reported to V8 with
+// MemoryPressureNotification.
+type MemoryPressureLevel int
+
+const (
+	// MemoryPressureLevelNone reports that memory pressure is back to normal.
+	MemoryPressureLevelNone MemoryPressureLevel = iota
+	// MemoryPressureLevelModerate asks V8 to free memory where it is cheap,
+	// such as by starting an incremental garbage collection.
+	MemoryPressureLevelModerate
+	// MemoryPressureLevelCritical asks V8 to free as much memory as possible
+	// right away, with a full garbage collection.
+	MemoryPressureLevelCritical
+)
+
+// GarbageCollectionType is the kind of garbage collection requested with
+// RequestGarbageCollectionForTesting.
+type GarbageCollectionType int
+
+const (
+	// FullGarbageCollection collects the whole heap.
+	FullGarbageCollection GarbageCollectionType = iota
+	// MinorGarbageCollection only collects the young generation.
+	MinorGarbageCollection
+)
+
+// LowMemoryNotification tells V8 that the system is running low on memory.
+// V8 uses this to collect as much garbage as possible, which may take a while.
+func (i *Isolate) LowMemoryNotification() {
+	C.IsolateLowMemoryNotification(i.ptr)
+}
+
+// MemoryPressureNotification tells V8 the level of memory pressure of the
+// system, so that it can adjust its garbage collection strategy.
+func (i *Isolate) MemoryPressureNotification(level MemoryPressureLevel) {
+	C.IsolateMemoryPressureNotification(i.ptr, C.int(level))
+}
+
+// IdleNotificationDeadline tells V8 that the embedder is idle for the given
+// duration, which V8 may use to perform garbage collection. Returns true if
+// there is no more garbage collection work to be done.
+func (i *Isolate) IdleNotificationDeadline(idleTime time.Duration) bool {
+	return C.IsolateIdleNotificationDeadline(i.ptr, C.double(idleTime.Seconds())) != 0
+}
+
+// AdjustAmountOfExternalAllocatedMemory tells V8 how much memory is kept alive
+// by JavaScript objects outside of the V8 heap, such as Go memory referenced
+// from JS. The change may be negative. V8 uses this to decide when to perform
+// garbage collection. Returns the adjusted amount of external memory in bytes.
+func (i *Isolate) AdjustAmountOfExternalAllocatedMemory(changeInBytes int64) int64 {
+	return int64(C.IsolateAdjustAmountOfExternalAllocatedMemory(i.ptr, C.int64_t(changeInBytes)))
+}
+
+// RequestGarbageCollectionForTesting releases the values that are no longer
+// referenced from Go (see GetTracedValueCnt) and then forces a garbage
+// collection of the given type. It requires V8 to have been set up with
+// SetFlags("--expose-gc") and should only be used in tests.
+func (i *Isolate) RequestGarbageCollectionForTesting(typ GarbageCollectionType) {
+	i.TryReleaseValuePtrInC(true)
+	C.IsolateRequestGarbageCollectionForTesting(i.ptr, C.int(typ))
+}
+
 // Dispose will dispose the Isolate VM; subsequent calls will panic.
+// Tasks still queued with Run or Post are dropped. When called off the
+// executor, Dispose waits for the running task, if any, to return.
 func (i *Isolate) Dispose() {
+	if e := i.stopExecutor(); e != nil && !e.onThread() {
+		<-e.exited
+	}
 	i.stopLock.Lock()
 	defer i.stopLock.Unlock()
 	i.stopped = true
@@ -296,6 +546,20 @@ func (i *Isolate) Dispose() {
 	i.templates = nil
 	C.IsolateDispose(i.ptr)
 	i.ptr = nil
+	i.releaseWeakRefs()
+	i.exceptionsLock.Lock()
+	i.exceptions = nil
+	i.exceptionsLock.Unlock()
+	i.cbMutex.Lock()
+	i.cbs = make(map[int]FunctionCallback)
+	i.props = make(map[int]*propertyHandler)
+	i.cbMutex.Unlock()
+}
+
+// EnqueueMicrotask queues fn to be called when the microtask queue is next run,
+// e.g. by (*Context).PerformMicrotaskCheckpoint.
+func (i *Isolate) EnqueueMicrotask(fn *Function) {
+	C.IsolateEnqueueMicrotask(i.ptr, fn.ptr)
 }
 
 // ThrowException schedules an exception to be thrown when returning to
@@ -333,6 +597,24 @@ func (i *Isolate) getCallback(ref int) FunctionCallback {
 	return i.cbs[ref]
 }
 
+func (i *Isolate) unregisterCallback(ref int) {
+	i.cbMutex.Lock()
+	defer i.cbMutex.Unlock()
+	delete(i.cbs, ref)
+}
+
+// CallbackCount returns the number of Go function, accessor and interceptor
+// callbacks that the isolate keeps alive. Callbacks of templates are kept
+// until the isolate is disposed, while those of functions created with
+// NewFunction, such as Promise reactions and the accessors of
+// Object.SetAccessorProperty, are released once the function is garbage
+// collected.
+func (i *Isolate) CallbackCount() int {
+	i.cbMutex.RLock()
+	defer i.cbMutex.RUnlock()
+	return len(i.cbs) + len(i.props)
+}
+
 func (i *Isolate) BatchMarkCanReleaseInC(values ...*Value) {
 	i.stopLock.Lock()
 	defer i.stopLock.Unlock()
@@ -344,7 +626,16 @@ func (i *Isolate) BatchMarkCanReleaseInC(values ...*Value) {
 	i.canReleasedValuePtrLock.Lock()
 	defer i.canReleasedValuePtrLock.Unlock()
 	for _, v := range values {
+		if v == nil || i.isCachedValue(v) {
+			continue
+		}
 		runtime.SetFinalizer(v, nil)
 		i.MoveTracedPtrToCanReleaseMap(v.ptr, false)
 	}
 }
+
+// isCachedValue reports whether v is the null or undefined value cached by the
+// ISO, which lives as long as the ISO and must never be released.
+func (i *Isolate) isCachedValue(v *Value) bool {
+	return v == i.null || v == i.undefined
+}
@@ -0,0 +1,297 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// IsolatePoolOptions configures an IsolatePool.
+type IsolatePoolOptions struct {
+	// MinSize is the number of warm contexts created upfront by NewIsolatePool.
+	MinSize int
+
+	// MaxSize is the maximum number of contexts that can be checked out or idle
+	// at the same time. Defaults to MinSize, and must be at least 1.
+	MaxSize int
+
+	// IsolateOptions are the resource constraints each pooled Isolate is created with.
+	IsolateOptions IsolateOptions
+
+	// GlobalTemplate optionally creates the global template of the contexts of
+	// a pooled Isolate. It is called once per Isolate, and the template is
+	// reused for the fresh contexts created on Put.
+	GlobalTemplate func(iso *Isolate) *ObjectTemplate
+
+	// Prewarm is called once on every new Context, before it is handed out,
+	// and is the place to run bootstrap scripts.
+	Prewarm func(ctx *Context) error
+
+	// MaxUsedHeapSize discards a Context on Put when the used heap size of
+	// its Isolate exceeds it. Zero means no limit.
+	MaxUsedHeapSize uint64
+
+	// MaxTracedValues discards a Context on Put when its Isolate still traces
+	// more values than this (see Isolate.GetTracedValueCnt). Zero means no limit.
+	MaxTracedValues int
+}
+
+// IsolatePoolStats reports the utilization of an IsolatePool.
+type IsolatePoolStats struct {
+	// Idle is the number of warm contexts waiting to be checked out.
+	Idle int
+	// InUse is the number of contexts currently checked out.
+	InUse int
+	// MaxSize is the configured maximum number of contexts.
+	MaxSize int
+	// Created is the total number of contexts created by the pool.
+	Created uint64
+	// Discarded is the total number of contexts disposed of on Put.
+	Discarded uint64
+	// Gets is the total number of successful calls to Get.
+	Gets uint64
+	// Waits is the number of calls to Get that had to wait for a context.
+	Waits uint64
+}
+
+// poolEntry is a Context of an IsolatePool along with the global template
+// built once for its Isolate.
+type poolEntry struct {
+	ctx    *Context
+	global *ObjectTemplate
+}
+
+// IsolatePool keeps warm contexts, each in its own Isolate, ready to be
+// checked out with Get and returned with Put. A checked out Context is owned
+// by the caller until it is returned, so it must not be shared.
+type IsolatePool struct {
+	opts   IsolatePoolOptions
+	tokens chan struct{}
+
+	mu     sync.Mutex
+	idle   []*poolEntry
+	inUse  map[*Context]*poolEntry
+	closed bool
+	stats  IsolatePoolStats
+}
+
+// NewIsolatePool creates a pool and prewarms opts.MinSize contexts.
+func NewIsolatePool(opts IsolatePoolOptions) (*IsolatePool, error) {
+	if opts.MinSize < 0 {
+		return nil, errors.New("v8go: IsolatePool MinSize cannot be negative")
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = opts.MinSize
+	}
+	if opts.MaxSize < 1 || opts.MaxSize < opts.MinSize {
+		return nil, errors.New("v8go: IsolatePool MaxSize must be at least 1 and not less than MinSize")
+	}
+	p := &IsolatePool{
+		opts:   opts,
+		tokens: make(chan struct{}, opts.MaxSize),
+		inUse:  make(map[*Context]*poolEntry),
+	}
+	for i := 0; i < opts.MinSize; i++ {
+		e, err := p.newEntry(nil)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.idle = append(p.idle, e)
+	}
+	return p, nil
+}
+
+// Get checks out a warm Context, creating one if none is idle and the pool
+// is below MaxSize. When the pool is exhausted Get waits until a Context is
+// returned or ctx is done.
+func (p *IsolatePool) Get(ctx context.Context) (*Context, error) {
+	select {
+	case p.tokens <- struct{}{}:
+	default:
+		p.mu.Lock()
+		p.stats.Waits++
+		p.mu.Unlock()
+		select {
+		case p.tokens <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		<-p.tokens
+		return nil, errors.New("v8go: IsolatePool is closed")
+	}
+	var e *poolEntry
+	if n := len(p.idle); n > 0 {
+		e = p.idle[n-1]
+		p.idle[n-1] = nil
+		p.idle = p.idle[:n-1]
+	}
+	p.mu.Unlock()
+
+	if e == nil {
+		var err error
+		if e, err = p.newEntry(nil); err != nil {
+			<-p.tokens
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+	p.inUse[e.ctx] = e
+	p.stats.Gets++
+	p.mu.Unlock()
+	return e.ctx, nil
+}
+
+// Put returns a Context checked out with Get. The Context is closed, and its
+// Isolate is reused for a fresh Context, so that no globals leak between
+// users. The Isolate is disposed of instead when it exceeds the
+// MaxUsedHeapSize or MaxTracedValues thresholds, or when the pool is closed;
+// the pool is then refilled up to MinSize.
+func (p *IsolatePool) Put(c *Context) {
+	p.mu.Lock()
+	e, ok := p.inUse[c]
+	if !ok {
+		p.mu.Unlock()
+		panic("v8go: Context was not checked out from this IsolatePool")
+	}
+	delete(p.inUse, c)
+	closed := p.closed
+	p.mu.Unlock()
+
+	iso := c.Isolate()
+	c.Close()
+	iso.TryReleaseValuePtrInC(true)
+	var fresh *poolEntry
+	if !closed && !p.exceedsLimits(iso) {
+		// a failing Prewarm disposes of the Isolate
+		fresh, _ = p.newEntry(e)
+	} else {
+		iso.Dispose()
+	}
+
+	p.mu.Lock()
+	if fresh != nil && p.closed {
+		// the pool was closed meanwhile
+		defer disposeContext(fresh.ctx)
+		fresh = nil
+	}
+	if fresh != nil {
+		p.idle = append(p.idle, fresh)
+	} else {
+		p.stats.Discarded++
+	}
+	p.mu.Unlock()
+	<-p.tokens
+
+	if fresh == nil {
+		p.refill()
+	}
+}
+
+// refill creates idle contexts until the pool holds MinSize contexts again.
+// Failures are left to Get, which creates contexts on demand.
+func (p *IsolatePool) refill() {
+	for {
+		p.mu.Lock()
+		short := !p.closed && len(p.idle)+len(p.inUse) < p.opts.MinSize
+		p.mu.Unlock()
+		if !short {
+			return
+		}
+		e, err := p.newEntry(nil)
+		if err != nil {
+			return
+		}
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			disposeContext(e.ctx)
+			return
+		}
+		p.idle = append(p.idle, e)
+		p.mu.Unlock()
+	}
+}
+
+// Stats returns the current utilization of the pool.
+func (p *IsolatePool) Stats() IsolatePoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Idle = len(p.idle)
+	stats.InUse = len(p.inUse)
+	stats.MaxSize = p.opts.MaxSize
+	return stats
+}
+
+// Close disposes of all idle contexts. Contexts that are still checked out
+// are disposed of when they are returned with Put.
+func (p *IsolatePool) Close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mu.Unlock()
+	for _, e := range idle {
+		disposeContext(e.ctx)
+	}
+}
+
+func (p *IsolatePool) exceedsLimits(iso *Isolate) bool {
+	if p.opts.MaxTracedValues > 0 && iso.GetTracedValueCnt() > p.opts.MaxTracedValues {
+		return true
+	}
+	if p.opts.MaxUsedHeapSize > 0 && iso.GetHeapStatistics().UsedHeapSize > p.opts.MaxUsedHeapSize {
+		return true
+	}
+	return false
+}
+
+// newEntry creates a prewarmed Context in the Isolate of prev, reusing its
+// global template, or in a new Isolate if prev is nil. The Isolate is
+// disposed of if Prewarm fails.
+func (p *IsolatePool) newEntry(prev *poolEntry) (*poolEntry, error) {
+	e := &poolEntry{}
+	var iso *Isolate
+	if prev != nil {
+		iso = prev.ctx.Isolate()
+		e.global = prev.global
+	} else {
+		iso = NewIsolateWithOptions(p.opts.IsolateOptions)
+		if p.opts.GlobalTemplate != nil {
+			e.global = p.opts.GlobalTemplate(iso)
+		}
+	}
+	opts := []ContextOption{iso}
+	if e.global != nil {
+		opts = append(opts, e.global)
+	}
+	ctx := NewContextWithOptions(opts...)
+	if p.opts.Prewarm != nil {
+		if err := p.opts.Prewarm(ctx); err != nil {
+			disposeContext(ctx)
+			return nil, err
+		}
+	}
+	e.ctx = ctx
+	p.mu.Lock()
+	p.stats.Created++
+	p.mu.Unlock()
+	return e, nil
+}
+
+func disposeContext(ctx *Context) {
+	iso := ctx.Isolate()
+	ctx.Close()
+	iso.Dispose()
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestIsolatePool(t *testing.T) {
+	t.Parallel()
+
+	var prewarmed, globals int
+	pool, err := v8.NewIsolatePool(v8.IsolatePoolOptions{
+		MinSize: 1,
+		MaxSize: 2,
+		GlobalTemplate: func(iso *v8.Isolate) *v8.ObjectTemplate {
+			globals++
+			global := v8.NewObjectTemplate(iso)
+			global.Set("version", "v1.0.0")
+			return global
+		},
+		Prewarm: func(ctx *v8.Context) error {
+			prewarmed++
+			_, err := ctx.RunScript("const greet = (name) => `hello ${name} ${version}`", "bootstrap.js")
+			return err
+		},
+	})
+	fatalIf(t, err)
+	defer pool.Close()
+
+	if stats := pool.Stats(); stats.Idle != 1 || stats.Created != 1 || prewarmed != 1 {
+		t.Fatalf("expected one prewarmed context, got %+v", stats)
+	}
+
+	ctx1, err := pool.Get(context.Background())
+	fatalIf(t, err)
+	val, err := ctx1.RunScript("greet('pool')", "main.js")
+	fatalIf(t, err)
+	if val.String() != "hello pool v1.0.0" {
+		t.Errorf("unexpected value: %q", val.String())
+	}
+
+	ctx2, err := pool.Get(context.Background())
+	fatalIf(t, err)
+	if ctx1.Isolate() == ctx2.Isolate() {
+		t.Error("expected each context to have its own isolate")
+	}
+	if stats := pool.Stats(); stats.InUse != 2 || stats.Idle != 0 || stats.Created != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// the pool is exhausted until a context is returned
+	timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	_, err = ctx2.RunScript("var leaked = true", "leak.js")
+	fatalIf(t, err)
+	iso2 := ctx2.Isolate()
+	pool.Put(ctx2)
+	ctx3, err := pool.Get(context.Background())
+	fatalIf(t, err)
+	if ctx3 == ctx2 || ctx3.Isolate() != iso2 {
+		t.Error("expected a fresh context in the returned isolate")
+	}
+	val, err = ctx3.RunScript("typeof leaked + ' ' + greet('again')", "main.js")
+	fatalIf(t, err)
+	if val.String() != "undefined hello again v1.0.0" {
+		t.Errorf("expected a fresh prewarmed context, got %q", val.String())
+	}
+	pool.Put(ctx3)
+	pool.Put(ctx1)
+
+	if stats := pool.Stats(); stats.InUse != 0 || stats.Idle != 2 || stats.Gets != 3 || stats.Waits != 1 || stats.Created != 5 || prewarmed != 5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	// the global template is built once per isolate and reused on Put
+	if globals != 2 {
+		t.Errorf("expected 2 global templates, got %d", globals)
+	}
+}
+
+func TestIsolatePool_Discard(t *testing.T) {
+	t.Parallel()
+
+	pool, err := v8.NewIsolatePool(v8.IsolatePoolOptions{
+		MinSize:         1,
+		MaxUsedHeapSize: 1,
+	})
+	fatalIf(t, err)
+	defer pool.Close()
+
+	ctx, err := pool.Get(context.Background())
+	fatalIf(t, err)
+	pool.Put(ctx)
+
+	// the discarded context is replaced to keep MinSize warm contexts
+	if stats := pool.Stats(); stats.Discarded != 1 || stats.Idle != 1 || stats.Created != 2 {
+		t.Errorf("expected context to be discarded and replaced, got %+v", stats)
+	}
+	refilled, err := pool.Get(context.Background())
+	fatalIf(t, err)
+	if refilled.Isolate() == ctx.Isolate() {
+		t.Error("expected the replacement context to have a new isolate")
+	}
+	pool.Put(refilled)
+}
+
+func TestIsolatePool_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	if _, err := v8.NewIsolatePool(v8.IsolatePoolOptions{}); err == nil {
+		t.Error("expected error for zero MaxSize")
+	}
+	if _, err := v8.NewIsolatePool(v8.IsolatePoolOptions{MinSize: 2, MaxSize: 1}); err == nil {
+		t.Error("expected error for MaxSize less than MinSize")
+	}
+}
@@ -6,10 +6,12 @@ package v8go_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -159,6 +161,136 @@ func TestIsolateGetHeapStatistics(t *testing.T) {
 	}
 }
 
+func TestNewIsolateWithOptions_HeapLimitExceeded(t *testing.T) {
+	t.Parallel()
+
+	callbacks := map[string]func(calls *int) v8.NearHeapLimitCallback{
+		"nil": func(*int) v8.NearHeapLimitCallback { return nil },
+		"keep limit": func(calls *int) v8.NearHeapLimitCallback {
+			return func(_ *v8.Isolate, current, initial uint64) uint64 {
+				*calls++
+				return current
+			}
+		},
+	}
+	for name, callback := range callbacks {
+		name, callback := name, callback
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			iso := v8.NewIsolateWithOptions(v8.IsolateOptions{
+				MaxOldGenerationSize:  16 * 1024 * 1024,
+				NearHeapLimitCallback: callback(&calls),
+			})
+			defer iso.Dispose()
+			ctx := v8.NewContextWithOptions(iso)
+			defer ctx.Close()
+
+			// the array is only reachable from the function, so it can be collected afterwards
+			_, err := ctx.RunScript(`(() => { const a = []; while (true) { a.push(new Array(1e5).fill(1)); } })()`, "oom.js")
+			if !errors.Is(err, v8.ErrHeapLimitExceeded) {
+				t.Fatalf("expected ErrHeapLimitExceeded, got %v", err)
+			}
+			if name != "nil" && calls == 0 {
+				t.Error("expected the near heap limit callback to be called")
+			}
+
+			// the same isolate keeps running scripts that allocate within the limit
+			val, err := ctx.RunScript(`(() => { const a = []; for (let i = 0; i < 40; i++) a.push(new Array(1e4).fill(i)); return a.length; })()`, "resume.js")
+			fatalIf(t, err)
+			if val.Int32() != 40 {
+				t.Errorf("expected 40, got %v", val)
+			}
+
+			// and is terminated again when exceeding it once more
+			_, err = ctx.RunScript(`(() => { const a = []; while (true) { a.push(new Array(1e5).fill(1)); } })()`, "oom2.js")
+			if !errors.Is(err, v8.ErrHeapLimitExceeded) {
+				t.Fatalf("expected ErrHeapLimitExceeded again, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewIsolateWithOptions_GrowHeapLimit(t *testing.T) {
+	t.Parallel()
+
+	var grown bool
+	iso := v8.NewIsolateWithOptions(v8.IsolateOptions{
+		MaxOldGenerationSize: 16 * 1024 * 1024,
+		NearHeapLimitCallback: func(_ *v8.Isolate, current, initial uint64) uint64 {
+			if grown {
+				return current
+			}
+			grown = true
+			return current * 4
+		},
+	})
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	_, err := ctx.RunScript(`const a = []; for (let i = 0; i < 30; i++) { a.push(new Array(1e5).fill(1)); } a.length`, "grow.js")
+	fatalIf(t, err)
+	if !grown {
+		t.Error("expected the heap limit to be grown")
+	}
+	if limit := iso.GetHeapStatistics().HeapSizeLimit; limit <= 16*1024*1024 {
+		t.Errorf("expected heap size limit to be raised, got %d", limit)
+	}
+}
+
+func TestIsolateMemoryNotifications(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	iso.LowMemoryNotification()
+	iso.MemoryPressureNotification(v8.MemoryPressureLevelModerate)
+	iso.MemoryPressureNotification(v8.MemoryPressureLevelNone)
+	iso.IdleNotificationDeadline(10 * time.Millisecond)
+
+	before := iso.GetHeapStatistics().ExternalMemory
+	if got := iso.AdjustAmountOfExternalAllocatedMemory(1024 * 1024); got < 1024*1024 {
+		t.Errorf("expected at least 1MB of external memory, got %d", got)
+	}
+	if got := iso.AdjustAmountOfExternalAllocatedMemory(-1024 * 1024); uint64(got) != before {
+		t.Errorf("expected external memory to be restored to %d, got %d", before, got)
+	}
+}
+
+func TestIsolateRequestGarbageCollectionForTesting(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	baseline := iso.GetTracedValueCnt()
+
+	for i := 0; i < 100; i++ {
+		val, err := ctx.RunScript("new Array(1000).fill('garbage')", "garbage.js")
+		fatalIf(t, err)
+		val.MarkValuePtrCanReleaseInC()
+	}
+	if iso.GetTracedValueCnt() != baseline+100 {
+		t.Fatalf("expected %d traced values, got %d", baseline+100, iso.GetTracedValueCnt())
+	}
+	used := iso.GetHeapStatistics().UsedHeapSize
+
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	if cnt := iso.GetTracedValueCnt(); cnt != baseline {
+		t.Errorf("expected %d traced values after GC, got %d", baseline, cnt)
+	}
+	if after := iso.GetHeapStatistics().UsedHeapSize; after >= used {
+		t.Errorf("expected used heap size to shrink from %d, got %d", used, after)
+	}
+	iso.RequestGarbageCollectionForTesting(v8.MinorGarbageCollection)
+}
+
 func TestCallbackRegistry(t *testing.T) {
 	t.Parallel()
 
@@ -178,6 +310,14 @@ func TestCallbackRegistry(t *testing.T) {
 	if fmt.Sprintf("%p", cb1) != fmt.Sprintf("%p", cb) {
 		t.Errorf("unexpected callback function; want %p, got %p", cb, cb1)
 	}
+	if n := iso.CallbackCount(); n != 1 {
+		t.Errorf("expected 1 callback, got %d", n)
+	}
+
+	iso.Dispose()
+	if n := iso.CallbackCount(); n != 0 {
+		t.Errorf("expected no callback after Dispose, got %d", n)
+	}
 }
 
 func TestIsolateDispose(t *testing.T) {
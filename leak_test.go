@@ -15,6 +15,7 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	setTestFlags()
 	exitCode := m.Run()
 	v8go.DoLeakSanitizerCheck()
 	os.Exit(exitCode)
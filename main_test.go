@@ -0,0 +1,18 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+//go:build !leakcheck
+// +build !leakcheck
+
+package v8go_test
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	setTestFlags()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// Map is a JavaScript Map object. Keys and values can be any value, and are
+// compared with the SameValueZero algorithm.
+type Map struct {
+	*Object
+}
+
+// MapEntry is a key and value pair of a Map.
+type MapEntry struct {
+	Key   *Value
+	Value *Value
+}
+
+// NewMap creates a new, empty Map.
+func NewMap(ctx *Context) (*Map, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctx.iso.checkThread()
+	return &Map{&Object{NewValueStruct(C.NewValueMap(ctx.ptr), ctx.iso)}}, nil
+}
+
+// Set sets the value for the key in the Map. Keys and values can be Go
+// primitives, as supported by Object.Set, or values.
+func (m *Map) Set(key, val interface{}) error {
+	m.ISO.checkThread()
+	k, err := coerceValue(m.ISO, key)
+	if err != nil {
+		return err
+	}
+	v, err := coerceValue(m.ISO, val)
+	if err != nil {
+		return err
+	}
+	_, err = boolResult(m.ISO, C.MapSet(m.ptr, k.ptr, v.ptr))
+	return err
+}
+
+// Get returns the value for the key, or undefined if the Map has no such key.
+func (m *Map) Get(key interface{}) (*Value, error) {
+	m.ISO.checkThread()
+	k, err := coerceValue(m.ISO, key)
+	if err != nil {
+		return nil, err
+	}
+	return valueResult(m.ISO, C.MapGet(m.ptr, k.ptr))
+}
+
+// Has returns true if the Map has the key.
+func (m *Map) Has(key interface{}) (bool, error) {
+	m.ISO.checkThread()
+	k, err := coerceValue(m.ISO, key)
+	if err != nil {
+		return false, err
+	}
+	return boolResult(m.ISO, C.MapHas(m.ptr, k.ptr))
+}
+
+// Delete removes the key from the Map, and returns true if it was present.
+func (m *Map) Delete(key interface{}) (bool, error) {
+	m.ISO.checkThread()
+	k, err := coerceValue(m.ISO, key)
+	if err != nil {
+		return false, err
+	}
+	return boolResult(m.ISO, C.MapDelete(m.ptr, k.ptr))
+}
+
+// Size returns the number of entries in the Map.
+func (m *Map) Size() int {
+	return int(C.MapSize(m.ptr))
+}
+
+// Entries returns the entries of the Map in insertion order.
+func (m *Map) Entries() ([]MapEntry, error) {
+	m.ISO.checkThread()
+	arr := &Object{NewValueStruct(C.MapAsArray(m.ptr), m.ISO)}
+	n := uint32(arr.ArrayLen())
+	entries := make([]MapEntry, 0, n/2)
+	for i := uint32(0); i+1 < n; i += 2 {
+		key, err := arr.GetIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		val, err := arr.GetIdx(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, MapEntry{Key: key, Value: val})
+	}
+	return entries, nil
+}
+
+// ToMap converts the Map to a Go map, with the keys converted to strings like
+// `String(key)` in JS. Keys that convert to the same string are merged, the
+// last one winning, and Symbol keys return an error.
+func (m *Map) ToMap() (map[string]*Value, error) {
+	entries, err := m.Entries()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*Value, len(entries))
+	for _, e := range entries {
+		if e.Key.IsSymbol() {
+			return nil, fmt.Errorf("v8go: cannot convert Symbol key %s to a string", e.Key.DetailString())
+		}
+		out[e.Key.String()] = e.Value
+	}
+	return out, nil
+}
+
+// AsMap will cast the value to the Map type. If the value is not a Map then an
+// error is returned.
+func (v *Value) AsMap() (*Map, error) {
+	if !v.IsMap() {
+		return nil, errors.New("v8go: value is not a Map")
+	}
+	return &Map{&Object{v}}, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	if _, err := v8.NewMap(nil); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+
+	m, err := v8.NewMap(ctx)
+	fatalIf(t, err)
+	if !m.IsMap() {
+		t.Fatal("expected Map")
+	}
+	key, _ := v8.NewObjectTemplate(iso).NewInstance(ctx)
+	fatalIf(t, m.Set("a", int32(1)))
+	fatalIf(t, m.Set(int32(2), "two"))
+	fatalIf(t, m.Set(key, true))
+	if m.Size() != 3 {
+		t.Errorf("expected size 3, got %d", m.Size())
+	}
+
+	val, err := m.Get(int32(2))
+	fatalIf(t, err)
+	if val.String() != "two" {
+		t.Errorf("expected two, got %s", val.String())
+	}
+	if val, _ := m.Get("2"); !val.IsUndefined() {
+		t.Error("expected keys to be compared without conversion")
+	}
+	if ok, _ := m.Has(key); !ok {
+		t.Error("expected object key")
+	}
+	if ok, _ := m.Delete("a"); !ok {
+		t.Error("expected a to be deleted")
+	}
+	if ok, _ := m.Delete("a"); ok {
+		t.Error("expected a to be missing")
+	}
+
+	fatalIf(t, ctx.Global().Set("m", m))
+	val, err = ctx.RunScript("[...m.values()].join(',')", "map.js")
+	fatalIf(t, err)
+	if val.String() != "two,true" {
+		t.Errorf("expected two,true, got %s", val.String())
+	}
+}
+
+func TestMapEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("new Map([['x', 1], [2, 'y'], [null, 3]])", "map.js")
+	fatalIf(t, err)
+	m, err := val.AsMap()
+	fatalIf(t, err)
+
+	entries, err := m.Entries()
+	fatalIf(t, err)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Key.String() != "x" || entries[0].Value.Int32() != 1 {
+		t.Errorf("unexpected first entry %s: %s", entries[0].Key, entries[0].Value)
+	}
+	if !entries[2].Key.IsNull() {
+		t.Errorf("expected null key, got %s", entries[2].Key)
+	}
+
+	goMap, err := m.ToMap()
+	fatalIf(t, err)
+	if len(goMap) != 3 || goMap["2"].String() != "y" || goMap["null"].Int32() != 3 {
+		t.Errorf("unexpected map %v", goMap)
+	}
+
+	val, err = ctx.RunScript("new Map([[Symbol('s'), 1]])", "symbol.js")
+	fatalIf(t, err)
+	m, _ = val.AsMap()
+	if _, err := m.ToMap(); err == nil {
+		t.Error("expected error for a Symbol key")
+	}
+	if _, err := v8.Undefined(ctx.Isolate()).AsMap(); err == nil {
+		t.Error("expected error for a non Map")
+	}
+}
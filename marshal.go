@@ -0,0 +1,878 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// maxSafeInteger is the largest integer a JS Number holds without loss.
+const maxSafeInteger = 1<<53 - 1
+
+// MarshalError is returned by ToJS and FromJS when a value cannot be
+// converted. Path locates the value within the one being converted, such as
+// `.items[2].name`, and is empty for the value itself.
+type MarshalError struct {
+	Path string
+	Err  error
+}
+
+func (e *MarshalError) Error() string {
+	if e.Path == "" {
+		return "v8go: " + e.Err.Error()
+	}
+	return fmt.Sprintf("v8go: %s: %v", e.Path, e.Err)
+}
+
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+func marshalErrorf(path, format string, args ...interface{}) error {
+	return &MarshalError{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+var (
+	valuerType      = reflect.TypeOf((*Valuer)(nil)).Elem()
+	valuePtrType    = reflect.TypeOf((*Value)(nil))
+	objectPtrType   = reflect.TypeOf((*Object)(nil))
+	functionPtrType = reflect.TypeOf((*Function)(nil))
+	timeType        = reflect.TypeOf(time.Time{})
+	bigIntPtrType   = reflect.TypeOf((*big.Int)(nil))
+)
+
+// ToJS converts a Go value to a JS value in ctx, recursively:
+//
+//	nil, nil pointers, maps and slices -> null
+//	bool -> Boolean
+//	string -> String
+//	integers and floats -> Number, integers beyond ±(2^53-1) -> BigInt
+//	*big.Int -> BigInt
+//	time.Time -> Date
+//	[]byte -> Uint8Array, with a copy of the bytes
+//	slices and arrays -> Array
+//	maps with string or integer keys -> Object
+//	structs -> Object
+//	Valuer -> the value itself
+//
+// Struct fields are converted under their name, unless renamed with a
+// `js:"name"` tag. Fields tagged `js:"-"` and unexported fields are skipped, as
+// are empty fields tagged with the omitempty option, like encoding/json.
+// Anonymous struct fields without a tag are flattened into their parent, and
+// conflicting names are resolved like encoding/json: the least nested field
+// wins, then the tagged one, and names that remain ambiguous are skipped.
+//
+// Integers beyond Number.MAX_SAFE_INTEGER become BigInts so that no precision
+// is lost, which makes the JS type depend on the value: a BigInt cannot be
+// mixed with Numbers in arithmetic. Convert such values to float64 first to
+// always get a Number.
+//
+// Pointers, maps and slices that contain themselves return a MarshalError.
+func ToJS(ctx *Context, val interface{}) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctx.iso.checkThread()
+	m := &marshaler{ctx: ctx, visiting: make(map[interface{}]bool)}
+	return m.toJS(reflect.ValueOf(val), "")
+}
+
+type marshaler struct {
+	ctx *Context
+	// visiting holds the pointers, maps and slices being converted, to detect cycles
+	visiting map[interface{}]bool
+}
+
+// sliceKey identifies a slice in visiting, by its data and length like encoding/json.
+type sliceKey struct {
+	ptr uintptr
+	len int
+}
+
+func (m *marshaler) toJS(rv reflect.Value, path string) (*Value, error) {
+	iso := m.ctx.iso
+	if !rv.IsValid() {
+		return Null(iso), nil
+	}
+	if rv.Type().Implements(valuerType) {
+		if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+			return Null(iso), nil
+		}
+		return rv.Interface().(Valuer).value(), nil
+	}
+
+	switch rv.Type() {
+	case timeType:
+		return NewDate(m.ctx, rv.Interface().(time.Time))
+	case bigIntPtrType:
+		if rv.IsNil() {
+			return Null(iso), nil
+		}
+		return NewValue(iso, rv.Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return NewValue(iso, rv.Bool())
+	case reflect.String:
+		return NewValue(iso, rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if i > maxSafeInteger || i < -maxSafeInteger {
+			return NewValue(iso, big.NewInt(i))
+		}
+		return NewValue(iso, float64(i))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > maxSafeInteger {
+			return NewValue(iso, new(big.Int).SetUint64(u))
+		}
+		return NewValue(iso, float64(u))
+	case reflect.Float32, reflect.Float64:
+		return NewValue(iso, rv.Float())
+	case reflect.Interface:
+		if rv.IsNil() {
+			return Null(iso), nil
+		}
+		return m.toJS(rv.Elem(), path)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return Null(iso), nil
+		}
+		if err := m.enter(rv.Pointer(), path); err != nil {
+			return nil, err
+		}
+		defer m.leave(rv.Pointer())
+		return m.toJS(rv.Elem(), path)
+	case reflect.Slice:
+		if rv.IsNil() {
+			return Null(iso), nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return newUint8ArrayCopy(iso, rv.Bytes()), nil
+		}
+		if rv.Len() > 0 {
+			key := sliceKey{rv.Pointer(), rv.Len()}
+			if err := m.enter(key, path); err != nil {
+				return nil, err
+			}
+			defer m.leave(key)
+		}
+		return m.arrayToJS(rv, path)
+	case reflect.Array:
+		return m.arrayToJS(rv, path)
+	case reflect.Map:
+		if rv.IsNil() {
+			return Null(iso), nil
+		}
+		if err := m.enter(rv.Pointer(), path); err != nil {
+			return nil, err
+		}
+		defer m.leave(rv.Pointer())
+		return m.mapToJS(rv, path)
+	case reflect.Struct:
+		return m.structToJS(rv, path)
+	}
+	return nil, marshalErrorf(path, "unsupported type %s", rv.Type())
+}
+
+func (m *marshaler) enter(key interface{}, path string) error {
+	if m.visiting[key] {
+		return marshalErrorf(path, "cycle detected")
+	}
+	m.visiting[key] = true
+	return nil
+}
+
+func (m *marshaler) leave(key interface{}) {
+	delete(m.visiting, key)
+}
+
+func (m *marshaler) arrayToJS(rv reflect.Value, path string) (*Value, error) {
+	n := rv.Len()
+	elements := make([]*Value, n)
+	ptrs := make([]C.ValuePtr, n)
+	for i := 0; i < n; i++ {
+		elem, err := m.toJS(rv.Index(i), path+"["+strconv.Itoa(i)+"]")
+		if err != nil {
+			return nil, err
+		}
+		// keep the elements alive until the array holds them
+		elements[i] = elem
+		ptrs[i] = elem.ptr
+	}
+	var arrPtr *C.ValuePtr
+	if n > 0 {
+		arrPtr = &ptrs[0]
+	}
+	arr := NewValueStruct(C.NewValueArray(m.ctx.iso.ptr, arrPtr, C.int32_t(int32(n))), m.ctx.iso)
+	runtime.KeepAlive(elements)
+	return arr, nil
+}
+
+func (m *marshaler) mapToJS(rv reflect.Value, path string) (*Value, error) {
+	obj := m.newObject()
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return nil, &MarshalError{Path: path, Err: err}
+		}
+		val, err := m.toJS(iter.Value(), path+propertyPath(key))
+		if err != nil {
+			return nil, err
+		}
+		if err := setProperty(obj, key, val); err != nil {
+			return nil, &MarshalError{Path: path, Err: err}
+		}
+	}
+	return obj, nil
+}
+
+func (m *marshaler) structToJS(rv reflect.Value, path string) (*Value, error) {
+	obj := m.newObject()
+	for _, f := range cachedFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		val, err := m.toJS(fv, path+propertyPath(f.name))
+		if err != nil {
+			return nil, err
+		}
+		if err := setProperty(obj, f.name, val); err != nil {
+			return nil, &MarshalError{Path: path, Err: err}
+		}
+	}
+	return obj, nil
+}
+
+func (m *marshaler) newObject() *Value {
+	return NewValueStruct(C.NewValueObject(m.ctx.ptr), m.ctx.iso)
+}
+
+func setProperty(obj *Value, key string, val *Value) error {
+	if vkey, err := nulKey(obj.ISO, key); err != nil {
+		return err
+	} else if vkey != nil {
+		C.ObjectSetValueKey(obj.ptr, vkey.ptr, val.ptr)
+		return nil
+	}
+	ckey := C.CString(key)
+	defer FreeCPtr(unsafe.Pointer(ckey))
+	C.ObjectSet(obj.ptr, ckey, val.ptr)
+	return nil
+}
+
+func newUint8ArrayCopy(iso *Isolate, b []byte) *Value {
+	var data *C.uint8_t
+	if len(b) > 0 {
+		data = (*C.uint8_t)(unsafe.Pointer(&b[0]))
+	}
+	return NewValueStruct(C.NewValueUint8ArrayCopy(iso.ptr, data, C.size_t(len(b))), iso)
+}
+
+func mapKeyString(key reflect.Value) (string, error) {
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", key.Type())
+}
+
+// FromJS converts a JS value to the Go value pointed to by target, the
+// reverse of ToJS. Values are only converted to Go types of the same kind:
+// a Number or a BigInt to an integer or a float, a Date to a time.Time, a
+// Uint8Array or any other ArrayBufferView to a []byte, an Array to a slice or
+// an array and an object to a map or a struct. null and undefined set the
+// target to its zero value, and object properties that are undefined leave
+// struct fields untouched.
+//
+// Converted to interface{}, Numbers become float64, BigInts *big.Int, Dates
+// time.Time, ArrayBufferViews []byte, Arrays []interface{}, functions
+// *Function and other objects map[string]interface{}. *Value, *Object and
+// *Function targets receive the value itself.
+//
+// Objects and Arrays that contain themselves return a MarshalError.
+func FromJS(val *Value, target interface{}) error {
+	if val == nil {
+		return errors.New("v8go: Value is required")
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("v8go: FromJS target must be a non-nil pointer, got %T", target)
+	}
+	val.ISO.checkThread()
+	u := &unmarshaler{visiting: make(map[int][]*Object)}
+	return u.fromJS(val, rv.Elem(), "")
+}
+
+type unmarshaler struct {
+	// visiting holds the objects being converted by their identity hash, to detect cycles
+	visiting map[int][]*Object
+}
+
+func (u *unmarshaler) enter(obj *Object, path string) error {
+	hash := obj.GetIdentityHash()
+	for _, v := range u.visiting[hash] {
+		if v.SameValue(obj.Value) {
+			return marshalErrorf(path, "cycle detected")
+		}
+	}
+	u.visiting[hash] = append(u.visiting[hash], obj)
+	return nil
+}
+
+func (u *unmarshaler) leave(obj *Object) {
+	hash := obj.GetIdentityHash()
+	objs := u.visiting[hash]
+	if len(objs) == 1 {
+		delete(u.visiting, hash)
+		return
+	}
+	objs[len(objs)-1] = nil
+	u.visiting[hash] = objs[:len(objs)-1]
+}
+
+func (u *unmarshaler) fromJS(val *Value, rv reflect.Value, path string) error {
+	t := rv.Type()
+	if t == valuePtrType {
+		rv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if val.IsNullOrUndefined() {
+		rv.Set(reflect.Zero(t))
+		return nil
+	}
+
+	switch t {
+	case objectPtrType:
+		obj, err := val.AsObject()
+		if err != nil {
+			return typeMismatch(val, t, path)
+		}
+		rv.Set(reflect.ValueOf(obj))
+		return nil
+	case functionPtrType:
+		fn, err := val.AsFunction()
+		if err != nil {
+			return typeMismatch(val, t, path)
+		}
+		rv.Set(reflect.ValueOf(fn))
+		return nil
+	case timeType:
+		if !val.IsDate() {
+			return typeMismatch(val, t, path)
+		}
+		rv.Set(reflect.ValueOf(val.Time()))
+		return nil
+	case bigIntPtrType:
+		switch {
+		case val.IsBigInt():
+			rv.Set(reflect.ValueOf(val.BigInt()))
+		case val.IsNumber() && isIntegral(val.Number()):
+			b, _ := new(big.Float).SetFloat64(val.Number()).Int(nil)
+			rv.Set(reflect.ValueOf(b))
+		default:
+			return typeMismatch(val, t, path)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if !val.IsBoolean() {
+			return typeMismatch(val, t, path)
+		}
+		rv.SetBool(val.Boolean())
+	case reflect.String:
+		if !val.IsString() {
+			return typeMismatch(val, t, path)
+		}
+		rv.SetString(val.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := jsInteger(val)
+		if !ok || !i.IsInt64() || rv.OverflowInt(i.Int64()) {
+			return marshalErrorf(path, "cannot convert %s to %s", describe(val), t)
+		}
+		rv.SetInt(i.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, ok := jsInteger(val)
+		if !ok || !i.IsUint64() || rv.OverflowUint(i.Uint64()) {
+			return marshalErrorf(path, "cannot convert %s to %s", describe(val), t)
+		}
+		rv.SetUint(i.Uint64())
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case val.IsNumber():
+			rv.SetFloat(val.Number())
+		case val.IsBigInt():
+			f, _ := new(big.Float).SetInt(val.BigInt()).Float64()
+			rv.SetFloat(f)
+		default:
+			return typeMismatch(val, t, path)
+		}
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return marshalErrorf(path, "unsupported type %s", t)
+		}
+		v, err := u.fromJSInterface(val, path)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			rv.Set(reflect.Zero(t))
+		} else {
+			rv.Set(reflect.ValueOf(v))
+		}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return u.fromJS(val, rv.Elem(), path)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && val.IsArrayBufferView() {
+			rv.SetBytes(val.GetCopiedArrayBufferViewContents())
+			return nil
+		}
+		if !val.IsArray() {
+			return typeMismatch(val, t, path)
+		}
+		obj, _ := val.AsObject()
+		n := int(obj.ArrayLen())
+		slice := reflect.MakeSlice(t, n, n)
+		if err := u.arrayFromJS(obj, slice, n, path); err != nil {
+			return err
+		}
+		rv.Set(slice)
+	case reflect.Array:
+		if !val.IsArray() {
+			return typeMismatch(val, t, path)
+		}
+		obj, _ := val.AsObject()
+		n := int(obj.ArrayLen())
+		if n != rv.Len() {
+			return marshalErrorf(path, "cannot convert Array of length %d to %s", n, t)
+		}
+		return u.arrayFromJS(obj, rv, n, path)
+	case reflect.Map:
+		if !val.IsObject() || val.IsArray() {
+			return typeMismatch(val, t, path)
+		}
+		return u.mapFromJS(val, rv, path)
+	case reflect.Struct:
+		if !val.IsObject() || val.IsArray() {
+			return typeMismatch(val, t, path)
+		}
+		return u.structFromJS(val, rv, path)
+	default:
+		return marshalErrorf(path, "unsupported type %s", t)
+	}
+	return nil
+}
+
+func (u *unmarshaler) arrayFromJS(obj *Object, rv reflect.Value, n int, path string) error {
+	if err := u.enter(obj, path); err != nil {
+		return err
+	}
+	defer u.leave(obj)
+	for i := 0; i < n; i++ {
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+		elem, err := obj.GetIdx(uint32(i))
+		if err != nil {
+			return &MarshalError{Path: elemPath, Err: err}
+		}
+		if err := u.fromJS(elem, rv.Index(i), elemPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *unmarshaler) mapFromJS(val *Value, rv reflect.Value, path string) error {
+	t := rv.Type()
+	obj, _ := val.AsObject()
+	if err := u.enter(obj, path); err != nil {
+		return err
+	}
+	defer u.leave(obj)
+	keys, err := obj.Keys()
+	if err != nil {
+		return &MarshalError{Path: path, Err: err}
+	}
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(keys)))
+	}
+	for _, key := range keys {
+		elemPath := path + propertyPath(key)
+		kv := reflect.New(t.Key()).Elem()
+		switch t.Key().Kind() {
+		case reflect.String:
+			kv.SetString(key)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(key, 10, 64)
+			if err != nil || kv.OverflowInt(i) {
+				return marshalErrorf(elemPath, "cannot convert key %q to %s", key, t.Key())
+			}
+			kv.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, err := strconv.ParseUint(key, 10, 64)
+			if err != nil || kv.OverflowUint(u) {
+				return marshalErrorf(elemPath, "cannot convert key %q to %s", key, t.Key())
+			}
+			kv.SetUint(u)
+		default:
+			return marshalErrorf(path, "unsupported map key type %s", t.Key())
+		}
+
+		elem, err := obj.Get(key)
+		if err != nil {
+			return &MarshalError{Path: elemPath, Err: err}
+		}
+		ev := reflect.New(t.Elem()).Elem()
+		if err := u.fromJS(elem, ev, elemPath); err != nil {
+			return err
+		}
+		rv.SetMapIndex(kv, ev)
+	}
+	return nil
+}
+
+func (u *unmarshaler) structFromJS(val *Value, rv reflect.Value, path string) error {
+	obj, _ := val.AsObject()
+	if err := u.enter(obj, path); err != nil {
+		return err
+	}
+	defer u.leave(obj)
+	for _, f := range cachedFields(rv.Type()) {
+		fieldPath := path + propertyPath(f.name)
+		prop, err := obj.Get(f.name)
+		if err != nil {
+			return &MarshalError{Path: fieldPath, Err: err}
+		}
+		if prop.IsUndefined() {
+			continue
+		}
+		fv := allocFieldByIndex(rv, f.index)
+		if err := u.fromJS(prop, fv, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *unmarshaler) fromJSInterface(val *Value, path string) (interface{}, error) {
+	switch {
+	case val.IsNullOrUndefined():
+		return nil, nil
+	case val.IsBoolean():
+		return val.Boolean(), nil
+	case val.IsString():
+		return val.String(), nil
+	case val.IsNumber():
+		return val.Number(), nil
+	case val.IsBigInt():
+		return val.BigInt(), nil
+	case val.IsDate():
+		return val.Time(), nil
+	case val.IsArrayBufferView():
+		return val.GetCopiedArrayBufferViewContents(), nil
+	case val.IsFunction():
+		return val.AsFunction()
+	case val.IsArray():
+		var arr []interface{}
+		err := u.fromJS(val, reflect.ValueOf(&arr).Elem(), path)
+		return arr, err
+	case val.IsObject():
+		var obj map[string]interface{}
+		err := u.fromJS(val, reflect.ValueOf(&obj).Elem(), path)
+		return obj, err
+	}
+	return nil, marshalErrorf(path, "unsupported value %s", describe(val))
+}
+
+// jsInteger returns the integral value of a Number or BigInt.
+func jsInteger(val *Value) (*big.Int, bool) {
+	switch {
+	case val.IsBigInt():
+		return val.BigInt(), true
+	case val.IsNumber():
+		f := val.Number()
+		if !isIntegral(f) {
+			return nil, false
+		}
+		i, _ := new(big.Float).SetFloat64(f).Int(nil)
+		return i, true
+	}
+	return nil, false
+}
+
+func isIntegral(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f) && f == math.Trunc(f)
+}
+
+func typeMismatch(val *Value, t reflect.Type, path string) error {
+	return marshalErrorf(path, "cannot convert %s to %s", describe(val), t)
+}
+
+// describe names the JS type of val for error messages.
+func describe(val *Value) string {
+	switch {
+	case val.IsBoolean():
+		return "Boolean"
+	case val.IsString():
+		return "String"
+	case val.IsNumber():
+		return "Number " + val.String()
+	case val.IsBigInt():
+		return "BigInt " + val.String()
+	case val.IsSymbol():
+		return "Symbol"
+	case val.IsDate():
+		return "Date"
+	case val.IsFunction():
+		return "Function"
+	case val.IsArray():
+		return "Array"
+	case val.IsArrayBufferView():
+		return "ArrayBufferView"
+	}
+	return "Object"
+}
+
+// propertyPath returns the path element for the property key.
+func propertyPath(key string) string {
+	if isIdentifier(key) {
+		return "." + key
+	}
+	return "[" + strconv.Quote(key) + "]"
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+type marshalField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]marshalField
+
+// cachedFields returns the fields of struct type t converted by ToJS and FromJS.
+func cachedFields(t reflect.Type) []marshalField {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]marshalField)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]marshalField)
+}
+
+// typeFields returns the fields of struct type t, with anonymous struct
+// fields flattened into it one level at a time, like encoding/json. Of the
+// fields sharing a name, the least nested one is kept, then the tagged one;
+// names that remain ambiguous are dropped.
+func typeFields(t reflect.Type) []marshalField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []marshalField
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		// a struct embedded at several levels only contributes its least
+		// nested fields, and embedding it twice at one level makes them conflict
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get("js")
+				if tag == "-" {
+					continue
+				}
+				name, opts := tag, ""
+				if idx := strings.Index(tag, ","); idx >= 0 {
+					name, opts = tag[:idx], tag[idx+1:]
+				}
+				fieldIndex := append(append([]int(nil), e.index...), i)
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					if sf.PkgPath != "" && sf.Type.Kind() == reflect.Ptr {
+						// cannot be allocated through reflection
+						continue
+					}
+					next = append(next, embedded{typ: ft, index: fieldIndex})
+					continue
+				}
+				if sf.PkgPath != "" {
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				fields = append(fields, marshalField{
+					name:      name,
+					index:     fieldIndex,
+					omitEmpty: hasTagOption(opts, "omitempty"),
+					tagged:    tagged,
+				})
+			}
+		}
+		for _, e := range current {
+			visited[e.typ] = true
+		}
+	}
+
+	// keep the dominant field of each name, in the order of declaration
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			dominant = append(dominant, f)
+		}
+		i = j
+	}
+	sort.Slice(dominant, func(i, j int) bool {
+		return indexLess(dominant[i].index, dominant[j].index)
+	})
+	return dominant
+}
+
+// dominantField returns the field that wins among fields of the same name,
+// sorted by depth and then tagged first, or false if there is none.
+func dominantField(fields []marshalField) (marshalField, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return marshalField{}, false
+	}
+	return fields[0], true
+}
+
+func indexLess(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false instead
+// of panicking when an embedded struct pointer is nil.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex, but allocates nil
+// embedded struct pointers on the way.
+func allocFieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// hasTagOption reports whether the comma-separated tag options contain opt.
+func hasTagOption(opts, opt string) bool {
+	for opts != "" {
+		var name string
+		if i := strings.Index(opts, ","); i >= 0 {
+			name, opts = opts[:i], opts[i+1:]
+		} else {
+			name, opts = opts, ""
+		}
+		if name == opt {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).IsZero()
+		}
+	}
+	return false
+}
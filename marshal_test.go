@@ -0,0 +1,380 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+type marshalAddress struct {
+	City string `js:"city"`
+	Zip  string `js:"zip,omitempty"`
+}
+
+type marshalBase struct {
+	ID int64 `js:"id"`
+}
+
+type marshalUser struct {
+	marshalBase
+	Name     string          `js:"name"`
+	Tags     []string        `js:"tags"`
+	Address  *marshalAddress `js:"address"`
+	Scores   map[string]int  `js:"scores"`
+	Avatar   []byte          `js:"avatar"`
+	Created  time.Time       `js:"created"`
+	Balance  *big.Int        `js:"balance"`
+	Extra    interface{}     `js:"extra"`
+	Nickname string          `js:"nickname,omitempty"`
+	Secret   string          `js:"-"`
+	Counts   [2]uint8        `js:"counts"`
+	Labels   map[int]string  `js:"labels"`
+	Nested   [][]float64     `js:"nested"`
+	private  int
+}
+
+func TestToJS(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	created := time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.UTC)
+	user := marshalUser{
+		marshalBase: marshalBase{ID: 42},
+		Name:        "gopher",
+		Tags:        []string{"a", "b"},
+		Address:     &marshalAddress{City: "Paris"},
+		Scores:      map[string]int{"go": 10},
+		Avatar:      []byte{1, 2, 3},
+		Created:     created,
+		Balance:     new(big.Int).Lsh(big.NewInt(1), 70),
+		Extra:       map[string]interface{}{"ok": true},
+		Secret:      "hidden",
+		Counts:      [2]uint8{1, 2},
+		Labels:      map[int]string{7: "seven"},
+		Nested:      [][]float64{{1.5}, {}},
+	}
+	val, err := v8.ToJS(ctx, user)
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("user", val))
+
+	checks := map[string]string{
+		"user.id":                              "42",
+		"typeof user.id":                       "number",
+		"user.name":                            "gopher",
+		"user.tags.join(',')":                  "a,b",
+		"user.address.city":                    "Paris",
+		"'zip' in user.address":                "false",
+		"user.scores.go":                       "10",
+		"user.avatar instanceof Uint8Array":    "true",
+		"user.avatar.join(',')":                "1,2,3",
+		"user.created.toISOString()":           "2021-03-04T05:06:07.008Z",
+		"user.balance === 2n ** 70n":           "true",
+		"user.extra.ok":                        "true",
+		"'nickname' in user":                   "false",
+		"'Secret' in user || 'secret' in user": "false",
+		"'private' in user":                    "false",
+		"user.counts.join(',')":                "1,2",
+		"user.labels[7]":                       "seven",
+		"JSON.stringify(user.nested)":          "[[1.5],[]]",
+	}
+	for script, expected := range checks {
+		v, err := ctx.RunScript(script, "check.js")
+		fatalIf(t, err)
+		if v.String() != expected {
+			t.Errorf("%s: expected %q, got %q", script, expected, v.String())
+		}
+	}
+}
+
+func TestToJS_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	_, err := v8.ToJS(ctx, map[string]interface{}{"items": []interface{}{1, make(chan int)}})
+	var marshalErr *v8.MarshalError
+	if !errors.As(err, &marshalErr) {
+		t.Fatalf("expected MarshalError, got %v", err)
+	}
+	if marshalErr.Path != ".items[1]" {
+		t.Errorf("expected path .items[1], got %q", marshalErr.Path)
+	}
+
+	type node struct {
+		Next *node
+	}
+	cycle := &node{}
+	cycle.Next = cycle
+	if _, err := v8.ToJS(ctx, cycle); !errors.As(err, &marshalErr) {
+		t.Errorf("expected MarshalError for a cycle, got %v", err)
+	}
+
+	slice := []interface{}{nil}
+	slice[0] = slice
+	if _, err := v8.ToJS(ctx, slice); !errors.As(err, &marshalErr) || marshalErr.Path != "[0]" {
+		t.Errorf("expected MarshalError for a slice cycle at [0], got %v", err)
+	}
+	m := map[string]interface{}{}
+	m["self"] = m
+	if _, err := v8.ToJS(ctx, m); !errors.As(err, &marshalErr) || marshalErr.Path != ".self" {
+		t.Errorf("expected MarshalError for a map cycle at .self, got %v", err)
+	}
+	// the same slice twice is not a cycle
+	shared := []int{1}
+	if _, err := v8.ToJS(ctx, [][]int{shared, shared}); err != nil {
+		t.Errorf("expected no error for a shared slice, got %v", err)
+	}
+
+	if _, err := v8.ToJS(nil, 1); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+}
+
+type marshalEmbeddedA struct {
+	Name   string
+	Shared string
+	Tagged string
+}
+
+type marshalEmbeddedB struct {
+	Shared string
+	Tagged string `js:"Tagged"`
+}
+
+type marshalEmbeddedDeep struct {
+	marshalEmbeddedA
+}
+
+type marshalEmbedding struct {
+	marshalEmbeddedA
+	marshalEmbeddedB
+	*marshalEmbeddedDeep
+	Name string
+}
+
+func TestToJS_OmitEmpty(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	type options struct {
+		A string `js:"a,omitempty,string"`
+		B string `js:"b,string,omitempty"`
+		C string `js:",omitempty"`
+		D string `js:"d,omitemptyish"`
+	}
+	val, err := v8.ToJS(ctx, options{})
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+	keys, err := obj.Keys()
+	fatalIf(t, err)
+	if !reflect.DeepEqual(keys, []string{"d"}) {
+		t.Errorf("expected only the field without omitempty, got %v", keys)
+	}
+}
+
+func TestToJS_Embedded(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := v8.ToJS(ctx, marshalEmbedding{
+		marshalEmbeddedA: marshalEmbeddedA{Name: "a", Shared: "a", Tagged: "a"},
+		marshalEmbeddedB: marshalEmbeddedB{Shared: "b", Tagged: "b"},
+		Name:             "outer",
+	})
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("val", val))
+
+	// the least nested field wins, then the tagged one, and ties are dropped
+	res, err := ctx.RunScript("JSON.stringify(val)", "check.js")
+	fatalIf(t, err)
+	if expected := `{"Tagged":"b","Name":"outer"}`; res.String() != expected {
+		t.Errorf("expected %s, got %s", expected, res.String())
+	}
+}
+
+func TestToJS_Keys(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := v8.ToJS(ctx, map[string]int{"a\x00b": 1, "a": 2})
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("val", val))
+	res, err := ctx.RunScript("Object.keys(val).length + ' ' + val['a\\0b'] + ' ' + val.a", "check.js")
+	fatalIf(t, err)
+	if res.String() != "2 1 2" {
+		t.Errorf("expected keys with NUL bytes to be kept, got %q", res.String())
+	}
+}
+
+func TestToJS_Integers(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	const maxSafe = 1<<53 - 1
+	tests := []struct {
+		val      interface{}
+		expected string
+	}{
+		{int64(maxSafe), "number 9007199254740991"},
+		{int64(-maxSafe), "number -9007199254740991"},
+		{int64(maxSafe + 1), "bigint 9007199254740992"},
+		{int64(-maxSafe - 1), "bigint -9007199254740992"},
+		{uint64(1<<64 - 1), "bigint 18446744073709551615"},
+		{float64(maxSafe + 1), "number 9007199254740992"},
+	}
+	for _, tt := range tests {
+		val, err := v8.ToJS(ctx, tt.val)
+		fatalIf(t, err)
+		fatalIf(t, ctx.Global().Set("val", val))
+		res, err := ctx.RunScript("typeof val + ' ' + val", "check.js")
+		fatalIf(t, err)
+		if res.String() != tt.expected {
+			t.Errorf("%T(%v): expected %q, got %q", tt.val, tt.val, tt.expected, res.String())
+		}
+	}
+}
+
+func TestFromJS(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({
+		id: 42,
+		name: "gopher",
+		tags: ["a", "b"],
+		address: { city: "Paris" },
+		scores: { go: 10 },
+		avatar: new Uint8Array([1, 2, 3]),
+		created: new Date("2021-03-04T05:06:07.008Z"),
+		balance: 2n ** 70n,
+		extra: { list: [1, "two", null], when: new Date(0) },
+		counts: [1, 2],
+		labels: { 7: "seven" },
+		nested: [[1.5], []],
+		Secret: "ignored",
+	})`, "user.js")
+	fatalIf(t, err)
+
+	user := marshalUser{Nickname: "kept"}
+	fatalIf(t, v8.FromJS(val, &user))
+
+	expected := marshalUser{
+		marshalBase: marshalBase{ID: 42},
+		Name:        "gopher",
+		Tags:        []string{"a", "b"},
+		Address:     &marshalAddress{City: "Paris"},
+		Scores:      map[string]int{"go": 10},
+		Avatar:      []byte{1, 2, 3},
+		Created:     time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.UTC),
+		Balance:     new(big.Int).Lsh(big.NewInt(1), 70),
+		Extra: map[string]interface{}{
+			"list": []interface{}{float64(1), "two", nil},
+			"when": time.Unix(0, 0),
+		},
+		Nickname: "kept",
+		Counts:   [2]uint8{1, 2},
+		Labels:   map[int]string{7: "seven"},
+		Nested:   [][]float64{{1.5}, {}},
+	}
+	if !user.Created.Equal(expected.Created) {
+		t.Errorf("expected %s, got %s", expected.Created, user.Created)
+	}
+	user.Created = expected.Created
+	if when, ok := user.Extra.(map[string]interface{})["when"].(time.Time); !ok || !when.Equal(time.Unix(0, 0)) {
+		t.Errorf("expected epoch date, got %v", user.Extra)
+	}
+	user.Extra.(map[string]interface{})["when"] = time.Unix(0, 0)
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("expected %+v, got %+v", expected, user)
+	}
+
+	var generic interface{}
+	fatalIf(t, v8.FromJS(val, &generic))
+	if name := generic.(map[string]interface{})["name"]; name != "gopher" {
+		t.Errorf("expected gopher, got %v", name)
+	}
+
+	var raw *v8.Value
+	fatalIf(t, v8.FromJS(val, &raw))
+	if raw != val {
+		t.Error("expected *Value target to receive the value")
+	}
+}
+
+func TestFromJS_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		target interface{}
+		path   string
+	}{
+		{`({ address: { city: 1 } })`, &marshalUser{}, ".address.city"},
+		{`({ tags: ["a", 2] })`, &marshalUser{}, ".tags[1]"},
+		{`({ counts: [1, 256] })`, &marshalUser{}, ".counts[1]"},
+		{`({ counts: [1] })`, &marshalUser{}, ".counts"},
+		{`({ id: 1.5 })`, &marshalUser{}, ".id"},
+		{`({ "a b": "x" })`, &map[string]int{}, `["a b"]`},
+		{`"str"`, new(int), ""},
+		{`(() => { const a = {}; a.self = a; return a })()`, new(map[string]interface{}), ".self"},
+		{`(() => { const a = { b: {} }; a.b.a = a; return a })()`, new(interface{}), ".b.a"},
+		{`(() => { const a = []; a.push(a); return a })()`, new([]interface{}), "[0]"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "value.js")
+		fatalIf(t, err)
+		err = v8.FromJS(val, tt.target)
+		var marshalErr *v8.MarshalError
+		if !errors.As(err, &marshalErr) {
+			t.Errorf("%s: expected MarshalError, got %v", tt.source, err)
+			continue
+		}
+		if marshalErr.Path != tt.path {
+			t.Errorf("%s: expected path %q, got %q", tt.source, tt.path, marshalErr.Path)
+		}
+	}
+
+	// the same object twice is not a cycle
+	val, err := ctx.RunScript(`(() => { const a = { x: 1 }; return [a, { a }] })()`, "shared.js")
+	fatalIf(t, err)
+	var shared []interface{}
+	fatalIf(t, v8.FromJS(val, &shared))
+
+	val, _ = v8.NewValue(ctx.Isolate(), "str")
+	var s string
+	if err := v8.FromJS(val, s); err == nil {
+		t.Error("expected error for a non pointer target")
+	}
+}
@@ -14,3 +14,19 @@ func FreeCPtr(ptr unsafe.Pointer) {
 func FreeModuleCPtr(ptr unsafe.Pointer) {
 	C.freeV8GoPtr(ptr)
 }
+
+// moduleCBytes is like C.CBytes, for memory freed by the module with
+// freeV8GoPtr rather than with FreeCPtr.
+func moduleCBytes(b []byte) unsafe.Pointer {
+	p := C.mallocV8GoPtr(C.size_t(len(b)))
+	if len(b) > 0 {
+		copy((*[1 << 30]byte)(p)[:len(b):len(b)], b)
+	}
+	return p
+}
+
+// moduleCString is like C.CString, for strings freed by the module with
+// freeV8GoPtr rather than with FreeCPtr.
+func moduleCString(s string) *C.char {
+	return (*C.char)(moduleCBytes(append([]byte(s), 0)))
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"unsafe"
 )
 
@@ -20,11 +21,7 @@ type Object struct {
 }
 
 func (o *Object) MethodCall(methodName string, args ...Valuer) (*Value, error) {
-	ckey := C.CString(methodName)
-	defer FreeCPtr(unsafe.Pointer(ckey))
-
-	getRtn := C.ObjectGet(o.ptr, ckey)
-	prop, err := valueResult(o.ISO, getRtn)
+	prop, err := o.Get(methodName)
 	if err != nil {
 		return nil, err
 	}
@@ -49,11 +46,24 @@ func coerceValue(iso *Isolate, val interface{}) (*Value, error) {
 	}
 }
 
+// nulKey returns a string value for a key containing NUL bytes, which cannot
+// be passed to C as a C string, or nil for any other key.
+func nulKey(iso *Isolate, key string) (*Value, error) {
+	if strings.IndexByte(key, 0) < 0 {
+		return nil, nil
+	}
+	if !hasExtensions {
+		return nil, errors.New("v8go: keys with NUL bytes are not supported by the linked libv8_export")
+	}
+	return NewValue(iso, key)
+}
+
 // Set will set a property on the Object to a given value.
 // Supports all value types, eg: Object, Array, Date, Set, Map etc
 // If the value passed is a Go supported primitive (string, int32, uint32, int64, uint64, float64, big.Int)
 // then a *Value will be created and set as the value property.
 func (o *Object) Set(key string, val interface{}) error {
+	o.ISO.checkThread()
 	if len(key) == 0 {
 		return errors.New("v8go: You must provide a valid property key")
 	}
@@ -63,6 +73,13 @@ func (o *Object) Set(key string, val interface{}) error {
 		return err
 	}
 
+	if vkey, err := nulKey(o.ISO, key); err != nil {
+		return err
+	} else if vkey != nil {
+		C.ObjectSetValueKey(o.ptr, vkey.ptr, value.ptr)
+		return nil
+	}
+
 	ckey := C.CString(key)
 	defer FreeCPtr(unsafe.Pointer(ckey))
 	C.ObjectSet(o.ptr, ckey, value.ptr)
@@ -74,6 +91,7 @@ func (o *Object) Set(key string, val interface{}) error {
 // If the value passed is a Go supported primitive (string, int32, uint32, int64, uint64, float64, big.Int)
 // then a *Value will be created and set as the value property.
 func (o *Object) SetIdx(idx uint32, val interface{}) error {
+	o.ISO.checkThread()
 	value, err := coerceValue(o.ISO, val)
 	if err != nil {
 		return err
@@ -110,6 +128,13 @@ func (o *Object) InternalFieldCount() uint32 {
 
 // Get tries to get a Value for a given Object property key.
 func (o *Object) Get(key string) (*Value, error) {
+	o.ISO.checkThread()
+	if vkey, err := nulKey(o.ISO, key); err != nil {
+		return nil, err
+	} else if vkey != nil {
+		return valueResult(o.ISO, C.ObjectGetValueKey(o.ptr, vkey.ptr))
+	}
+
 	ckey := C.CString(key)
 	defer FreeCPtr(unsafe.Pointer(ckey))
 
@@ -131,6 +156,7 @@ func (o *Object) GetInternalField(idx uint32) *Value {
 
 // GetIdx tries to get a Value at a give Object index.
 func (o *Object) GetIdx(idx uint32) (*Value, error) {
+	o.ISO.checkThread()
 	rtn := C.ObjectGetIdx(o.ptr, C.uint32_t(idx))
 	return valueResult(o.ISO, rtn)
 }
@@ -138,6 +164,12 @@ func (o *Object) GetIdx(idx uint32) (*Value, error) {
 // Has calls the abstract operation HasProperty(O, P) described in ECMA-262, 7.3.10.
 // Returns true, if the object has the property, either own or on the prototype chain.
 func (o *Object) Has(key string) bool {
+	o.ISO.checkThread()
+	if vkey, err := nulKey(o.ISO, key); err != nil {
+		return false
+	} else if vkey != nil {
+		return C.ObjectHasValueKey(o.ptr, vkey.ptr) != 0
+	}
 	ckey := C.CString(key)
 	defer FreeCPtr(unsafe.Pointer(ckey))
 	return C.ObjectHas(o.ptr, ckey) != 0
@@ -150,6 +182,12 @@ func (o *Object) HasIdx(idx uint32) bool {
 
 // Delete returns true if successful in deleting a named property on the object.
 func (o *Object) Delete(key string) bool {
+	o.ISO.checkThread()
+	if vkey, err := nulKey(o.ISO, key); err != nil {
+		return false
+	} else if vkey != nil {
+		return C.ObjectDeleteValueKey(o.ptr, vkey.ptr) != 0
+	}
 	ckey := C.CString(key)
 	defer FreeCPtr(unsafe.Pointer(ckey))
 	return C.ObjectDelete(o.ptr, ckey) != 0
@@ -163,3 +201,411 @@ func (o *Object) DeleteIdx(idx uint32) bool {
 func (o *Object) ArrayLen() int64 {
 	return int64(C.GetArrayLen(o.ptr))
 }
+
+// PropertyFilter selects the properties returned by OwnPropertyNames. The
+// filters can be combined with a bitwise or.
+type PropertyFilter int
+
+const (
+	AllProperties    PropertyFilter = 0
+	OnlyWritable     PropertyFilter = 1
+	OnlyEnumerable   PropertyFilter = 2
+	OnlyConfigurable PropertyFilter = 4
+	SkipStrings      PropertyFilter = 8
+	SkipSymbols      PropertyFilter = 16
+)
+
+// PropertyDescriptor describes an own property of an object, see
+// GetOwnPropertyDescriptor. Data properties have a Value and Writable, while
+// accessor properties have a Get and/or Set function instead.
+type PropertyDescriptor struct {
+	Value        *Value
+	Get          *Function
+	Set          *Function
+	Writable     bool
+	Enumerable   bool
+	Configurable bool
+}
+
+// IsAccessor returns true if the descriptor is for an accessor property.
+func (d *PropertyDescriptor) IsAccessor() bool {
+	return d.Get != nil || d.Set != nil
+}
+
+// Keys returns the names of the own enumerable string-keyed properties of the
+// object, in the same order as `Object.keys` in JS.
+func (o *Object) Keys() ([]string, error) {
+	o.ISO.checkThread()
+	arr, err := objectResult(o.ISO, C.ObjectKeys(o.ptr))
+	if err != nil {
+		return nil, err
+	}
+	n := uint32(arr.ArrayLen())
+	keys := make([]string, 0, n)
+	for i := uint32(0); i < n; i++ {
+		key, err := arr.GetIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.String())
+	}
+	return keys, nil
+}
+
+// OwnPropertyNames returns the keys of the own properties of the object that
+// match filter, including non-enumerable ones unless filtered out with
+// OnlyEnumerable. Keys are strings, integer indices included, or Symbols.
+func (o *Object) OwnPropertyNames(filter PropertyFilter) ([]*Value, error) {
+	o.ISO.checkThread()
+	arr, err := objectResult(o.ISO, C.ObjectGetOwnPropertyNames(o.ptr, C.int(filter)))
+	if err != nil {
+		return nil, err
+	}
+	n := uint32(arr.ArrayLen())
+	keys := make([]*Value, 0, n)
+	for i := uint32(0); i < n; i++ {
+		key, err := arr.GetIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// HasOwn returns true if the object has an own property with the given key,
+// ignoring the prototype chain, like `Object.hasOwn` in JS.
+func (o *Object) HasOwn(key string) bool {
+	o.ISO.checkThread()
+	vkey, err := NewValue(o.ISO, key)
+	if err != nil {
+		return false
+	}
+	return C.ObjectHasOwnProperty(o.ptr, vkey.ptr) != 0
+}
+
+// GetOwnPropertyDescriptor returns the descriptor of the own property with the
+// given key, or nil if the object has no such own property.
+func (o *Object) GetOwnPropertyDescriptor(key string) (*PropertyDescriptor, error) {
+	o.ISO.checkThread()
+	vkey, err := NewValue(o.ISO, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rtn := C.ObjectGetOwnPropertyDescriptor(o.ptr, vkey.ptr)
+	val, err := valueResult(o.ISO, rtn)
+	if err != nil {
+		return nil, err
+	}
+	if val.IsUndefined() {
+		return nil, nil
+	}
+	desc, _ := val.AsObject()
+
+	d := &PropertyDescriptor{}
+	for _, flag := range []struct {
+		name string
+		dst  *bool
+	}{
+		{"writable", &d.Writable},
+		{"enumerable", &d.Enumerable},
+		{"configurable", &d.Configurable},
+	} {
+		v, err := desc.Get(flag.name)
+		if err != nil {
+			return nil, err
+		}
+		*flag.dst = v.Boolean()
+	}
+	for _, accessor := range []struct {
+		name string
+		dst  **Function
+	}{
+		{"get", &d.Get},
+		{"set", &d.Set},
+	} {
+		v, err := desc.Get(accessor.name)
+		if err != nil {
+			return nil, err
+		}
+		if v.IsFunction() {
+			*accessor.dst, _ = v.AsFunction()
+		}
+	}
+	if !d.IsAccessor() {
+		if d.Value, err = desc.Get("value"); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Entries returns an iterator over the own enumerable string-keyed properties
+// of the object, like `Object.entries` in JS. The keys are collected when
+// Entries is called, while each value is read as the iterator reaches it.
+//
+//	it := obj.Entries()
+//	for it.Next() {
+//		fmt.Println(it.Key(), it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+func (o *Object) Entries() *PropertyIterator {
+	keys, err := o.Keys()
+	return &PropertyIterator{obj: o, keys: keys, idx: -1, err: err}
+}
+
+// PropertyIterator iterates over the properties of an Object, see Entries.
+type PropertyIterator struct {
+	obj   *Object
+	keys  []string
+	idx   int
+	value *Value
+	err   error
+}
+
+// Next advances the iterator to the next property, and returns false when
+// there are no more properties or reading a value failed, see Err.
+func (it *PropertyIterator) Next() bool {
+	if it.err != nil || it.idx+1 >= len(it.keys) {
+		it.value = nil
+		return false
+	}
+	it.idx++
+	it.value, it.err = it.obj.Get(it.keys[it.idx])
+	return it.err == nil
+}
+
+// Key returns the key of the current property.
+func (it *PropertyIterator) Key() string {
+	if it.idx < 0 || it.idx >= len(it.keys) {
+		return ""
+	}
+	return it.keys[it.idx]
+}
+
+// Value returns the value of the current property.
+func (it *PropertyIterator) Value() *Value {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any, such as an
+// exception thrown by a getter.
+func (it *PropertyIterator) Err() error {
+	return it.err
+}
+
+// SetSymbol will set a symbol-keyed property on the Object to a given value.
+// Supports the same value types as Set.
+func (o *Object) SetSymbol(key *Symbol, val interface{}) error {
+	o.ISO.checkThread()
+	if key == nil {
+		return errors.New("v8go: You must provide a valid property key")
+	}
+
+	value, err := coerceValue(o.ISO, val)
+	if err != nil {
+		return err
+	}
+
+	C.ObjectSetValueKey(o.ptr, key.ptr, value.ptr)
+	return nil
+}
+
+// GetSymbol tries to get a Value for a given Object symbol-keyed property.
+func (o *Object) GetSymbol(key *Symbol) (*Value, error) {
+	o.ISO.checkThread()
+	rtn := C.ObjectGetValueKey(o.ptr, key.ptr)
+	return valueResult(o.ISO, rtn)
+}
+
+// HasSymbol returns true if the object has the symbol-keyed property, either
+// own or on the prototype chain.
+func (o *Object) HasSymbol(key *Symbol) bool {
+	o.ISO.checkThread()
+	return C.ObjectHasValueKey(o.ptr, key.ptr) != 0
+}
+
+// DeleteSymbol returns true if successful in deleting a symbol-keyed property
+// on the object.
+func (o *Object) DeleteSymbol(key *Symbol) bool {
+	o.ISO.checkThread()
+	return C.ObjectDeleteValueKey(o.ptr, key.ptr) != 0
+}
+
+func boolResult(iso *Isolate, rtn C.RtnBool) (bool, error) {
+	if rtn.error.msg != nil {
+		return false, iso.executionError(newJSError(iso, rtn.error))
+	}
+	return rtn.value != 0, nil
+}
+
+// DefineOwnProperty defines an own data property on the Object with the given
+// attributes, like `Object.defineProperty` in JS, replacing any existing
+// configurable property with the key. Supports the same value types as Set.
+func (o *Object) DefineOwnProperty(key string, val interface{}, attributes ...PropertyAttribute) error {
+	o.ISO.checkThread()
+	if len(key) == 0 {
+		return errors.New("v8go: You must provide a valid property key")
+	}
+	var attrs PropertyAttribute
+	for _, a := range attributes {
+		attrs |= a
+	}
+
+	value, err := coerceValue(o.ISO, val)
+	if err != nil {
+		return err
+	}
+	vkey, err := NewValue(o.ISO, key)
+	if err != nil {
+		return err
+	}
+
+	ok, err := boolResult(o.ISO, C.ObjectDefineOwnProperty(o.ptr, vkey.ptr, value.ptr, C.int(attrs)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("v8go: cannot redefine property: %s", key)
+	}
+	return nil
+}
+
+// SetAccessorProperty defines an own accessor property on the Object whose
+// value is computed by getter, and assigned through setter, which receives
+// the assigned value as its only argument. Either can be nil: a property
+// without a setter is read-only and one without a getter reads as undefined.
+// The callbacks are released once their accessor functions, which can outlive
+// the Object, are garbage collected, see NewFunction.
+func (o *Object) SetAccessorProperty(key string, getter, setter FunctionCallback, attributes ...PropertyAttribute) error {
+	o.ISO.checkThread()
+	if len(key) == 0 {
+		return errors.New("v8go: You must provide a valid property key")
+	}
+	if getter == nil && setter == nil {
+		return errors.New("v8go: accessor property requires a getter or a setter")
+	}
+	var attrs PropertyAttribute
+	for _, a := range attributes {
+		attrs |= a
+	}
+	vkey, err := NewValue(o.ISO, key)
+	if err != nil {
+		return err
+	}
+	ctx, err := o.CreationContext()
+	if err != nil {
+		return err
+	}
+
+	var getterPtr, setterPtr C.ValuePtr
+	for _, accessor := range []struct {
+		cb  FunctionCallback
+		dst *C.ValuePtr
+	}{
+		{getter, &getterPtr},
+		{setter, &setterPtr},
+	} {
+		if accessor.cb == nil {
+			continue
+		}
+		fn, err := NewFunction(ctx, accessor.cb)
+		if err != nil {
+			return err
+		}
+		defer o.ISO.BatchMarkCanReleaseInC(fn.Value)
+		*accessor.dst = fn.ptr
+	}
+
+	ok, err := boolResult(o.ISO, C.ObjectSetAccessorProperty(o.ptr, vkey.ptr, getterPtr, setterPtr, C.int(attrs)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("v8go: cannot redefine property: %s", key)
+	}
+	return nil
+}
+
+// IntegrityLevel is the level passed to SetIntegrityLevel.
+type IntegrityLevel int
+
+const (
+	// Frozen prevents adding, removing and changing properties, like
+	// `Object.freeze` in JS.
+	Frozen IntegrityLevel = iota
+	// Sealed prevents adding and removing properties, like `Object.seal`
+	// in JS, while writable properties can still be changed.
+	Sealed
+)
+
+// GetPrototype returns the prototype of the Object, which is null for
+// objects without one.
+func (o *Object) GetPrototype() (*Value, error) {
+	o.ISO.checkThread()
+	return valueResult(o.ISO, C.ObjectGetPrototype(o.ptr))
+}
+
+// SetPrototype sets the prototype of the Object, like `Object.setPrototypeOf`
+// in JS. proto must be an Object or null.
+func (o *Object) SetPrototype(proto Valuer) error {
+	o.ISO.checkThread()
+	if proto == nil || proto.value() == nil {
+		return errors.New("v8go: prototype must be an Object or null")
+	}
+	p := proto.value()
+	if !p.IsObject() && !p.IsNull() {
+		return errors.New("v8go: prototype must be an Object or null")
+	}
+	ok, err := boolResult(o.ISO, C.ObjectSetPrototype(o.ptr, p.ptr))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("v8go: cannot set prototype")
+	}
+	return nil
+}
+
+// SetIntegrityLevel freezes or seals the Object.
+func (o *Object) SetIntegrityLevel(level IntegrityLevel) error {
+	o.ISO.checkThread()
+	ok, err := boolResult(o.ISO, C.ObjectSetIntegrityLevel(o.ptr, C.int(level)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("v8go: cannot change the integrity level of the object")
+	}
+	return nil
+}
+
+// GetConstructorName returns the name of the function that constructed the
+// Object, such as "Object" or the name of a class.
+func (o *Object) GetConstructorName() string {
+	o.ISO.checkThread()
+	s := C.ObjectGetConstructorName(o.ptr)
+	defer FreeModuleCPtr(unsafe.Pointer(s))
+	return C.GoString(s)
+}
+
+// GetIdentityHash returns the identity hash of the Object. It is the same
+// for the lifetime of the Object but is not unique: different objects may
+// share a hash.
+func (o *Object) GetIdentityHash() int {
+	o.ISO.checkThread()
+	return int(C.ObjectGetIdentityHash(o.ptr))
+}
+
+// CreationContext returns the Context the Object was created in.
+func (o *Object) CreationContext() (*Context, error) {
+	o.ISO.checkThread()
+	ctx := getContext(int(C.ObjectGetCreationContextRef(o.ptr)))
+	if ctx == nil {
+		return nil, errors.New("v8go: the creation context of the object is not available")
+	}
+	return ctx, nil
+}
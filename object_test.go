@@ -190,6 +190,348 @@ func TestObjectDelete(t *testing.T) {
 
 }
 
+func TestObjectKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, err := ctx.RunScript(`
+		const proto = { inherited: 1 };
+		const obj = Object.create(proto);
+		obj.b = 1;
+		obj.a = 2;
+		obj[1] = 3;
+		obj[Symbol('sym')] = 4;
+		Object.defineProperty(obj, 'hidden', { value: 5, enumerable: false });
+		obj`, "")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+
+	keys, err := obj.Keys()
+	fatalIf(t, err)
+	if fmt.Sprint(keys) != "[1 b a]" {
+		t.Errorf("expected [1 b a], got %v", keys)
+	}
+
+	tests := [...]struct {
+		filter   v8.PropertyFilter
+		expected string
+	}{
+		{v8.AllProperties, "[1 b a hidden Symbol(sym)]"},
+		{v8.OnlyEnumerable, "[1 b a Symbol(sym)]"},
+		{v8.OnlyEnumerable | v8.SkipSymbols, "[1 b a]"},
+		{v8.SkipStrings, "[Symbol(sym)]"},
+	}
+	for _, tt := range tests {
+		names, err := obj.OwnPropertyNames(tt.filter)
+		fatalIf(t, err)
+		var got []string
+		for _, name := range names {
+			if name.IsSymbol() {
+				got = append(got, name.DetailString())
+			} else {
+				got = append(got, name.String())
+			}
+		}
+		if fmt.Sprint(got) != tt.expected {
+			t.Errorf("filter %d: expected %s, got %v", tt.filter, tt.expected, got)
+		}
+	}
+}
+
+func TestObjectHasOwn(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript("const own = Object.create({ inherited: 1 }); own.mine = 1; own", "")
+	obj, _ := val.AsObject()
+	if !obj.HasOwn("mine") {
+		t.Error("expected own property")
+	}
+	if obj.HasOwn("inherited") {
+		t.Error("expected inherited property not to be own")
+	}
+	if !obj.Has("inherited") {
+		t.Error("expected Has to walk the prototype chain")
+	}
+}
+
+func TestObjectGetOwnPropertyDescriptor(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript(`
+		const desc = { data: 'x' };
+		Object.defineProperty(desc, 'readonly', { value: 1, writable: false, enumerable: false, configurable: false });
+		Object.defineProperty(desc, 'accessor', { get() { return 2 }, enumerable: true, configurable: true });
+		desc`, "")
+	obj, _ := val.AsObject()
+
+	d, err := obj.GetOwnPropertyDescriptor("data")
+	fatalIf(t, err)
+	if d.IsAccessor() || d.Value.String() != "x" || !d.Writable || !d.Enumerable || !d.Configurable {
+		t.Errorf("unexpected descriptor for data: %+v", d)
+	}
+
+	d, err = obj.GetOwnPropertyDescriptor("readonly")
+	fatalIf(t, err)
+	if d.Value.Int32() != 1 || d.Writable || d.Enumerable || d.Configurable {
+		t.Errorf("unexpected descriptor for readonly: %+v", d)
+	}
+
+	d, err = obj.GetOwnPropertyDescriptor("accessor")
+	fatalIf(t, err)
+	if !d.IsAccessor() || d.Get == nil || d.Set != nil || d.Value != nil || !d.Enumerable {
+		t.Fatalf("unexpected descriptor for accessor: %+v", d)
+	}
+	got, err := d.Get.Call(obj)
+	fatalIf(t, err)
+	if got.Int32() != 2 {
+		t.Errorf("expected getter to return 2, got %v", got)
+	}
+
+	d, err = obj.GetOwnPropertyDescriptor("missing")
+	fatalIf(t, err)
+	if d != nil {
+		t.Errorf("expected nil descriptor, got %+v", d)
+	}
+}
+
+func TestObjectEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript(`({ a: 1, b: 'two', get c() { throw new Error('boom') }, d: 4 })`, "")
+	obj, _ := val.AsObject()
+
+	var entries []string
+	it := obj.Entries()
+	for it.Next() {
+		entries = append(entries, it.Key()+"="+it.Value().String())
+	}
+	if fmt.Sprint(entries) != "[a=1 b=two]" {
+		t.Errorf("expected [a=1 b=two], got %v", entries)
+	}
+	if it.Err() == nil {
+		t.Error("expected error from the throwing getter")
+	}
+	if it.Next() {
+		t.Error("expected iteration to stop after an error")
+	}
+}
+
+func TestObjectDefineOwnProperty(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript("var config = {}; config", "")
+	obj, _ := val.AsObject()
+
+	fatalIf(t, obj.DefineOwnProperty("version", "1.0", v8.ReadOnly, v8.DontDelete))
+	fatalIf(t, obj.DefineOwnProperty("internal", int32(7), v8.DontEnum))
+
+	val, err := ctx.RunScript(`
+		"use strict";
+		let errors = 0;
+		try { config.version = "2.0" } catch (e) { errors++ }
+		try { delete config.version } catch (e) { errors++ }
+		[config.version, errors, Object.keys(config).join(','), config.internal].join('|')`, "")
+	fatalIf(t, err)
+	if val.String() != "1.0|2|version|7" {
+		t.Errorf("expected 1.0|2|version|7, got %s", val.String())
+	}
+
+	if err := obj.DefineOwnProperty("version", "2.0"); err == nil {
+		t.Error("expected error redefining a non-configurable property")
+	}
+	if err := obj.DefineOwnProperty("", "x"); err == nil {
+		t.Error("expected error for an empty key")
+	}
+}
+
+func TestObjectSetAccessorProperty(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript("var counter = {}; counter", "")
+	obj, _ := val.AsObject()
+
+	var count int32
+	getter := func(info *v8.FunctionCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, count)
+		return val
+	}
+	setter := func(info *v8.FunctionCallbackInfo) *v8.Value {
+		count = info.Args()[0].Int32()
+		return nil
+	}
+	fatalIf(t, obj.SetAccessorProperty("count", getter, setter))
+	fatalIf(t, obj.SetAccessorProperty("readonly", getter, nil, v8.DontEnum))
+
+	val, err := ctx.RunScript("counter.count = 41; counter.count++; counter.readonly = 0; [counter.count, counter.readonly, Object.keys(counter)].join('|')", "")
+	fatalIf(t, err)
+	if val.String() != "42|42|count" {
+		t.Errorf("expected 42|42|count, got %s", val.String())
+	}
+	if count != 42 {
+		t.Errorf("expected setter to be called, got %d", count)
+	}
+
+	if err := obj.SetAccessorProperty("none", nil, nil); err == nil {
+		t.Error("expected error without getter and setter")
+	}
+}
+
+func TestObjectAccessorOutlivesObject(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	count := iso.CallbackCount()
+	val, err := ctx.RunScript("var owner = {}; owner", "owner.js")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+	fatalIf(t, obj.SetAccessorProperty("answer", func(info *v8.FunctionCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, int32(42))
+		return val
+	}, nil))
+
+	// keep the getter while the object that defined it is collected
+	_, err = ctx.RunScript("var get = Object.getOwnPropertyDescriptor(owner, 'answer').get; owner = undefined", "get.js")
+	fatalIf(t, err)
+	iso.BatchMarkCanReleaseInC(val)
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	if iso.CallbackCount() != count+1 {
+		t.Fatalf("expected the getter callback to be kept, got %d callbacks instead of %d", iso.CallbackCount(), count+1)
+	}
+	res, err := ctx.RunScript("get()", "call.js")
+	fatalIf(t, err)
+	if res.Int32() != 42 {
+		t.Errorf("expected 42, got %s", res.DetailString())
+	}
+
+	_, err = ctx.RunScript("get = undefined", "release.js")
+	fatalIf(t, err)
+	iso.BatchMarkCanReleaseInC(res)
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	if iso.CallbackCount() != count {
+		t.Errorf("expected the getter callback to be released, got %d callbacks instead of %d", iso.CallbackCount(), count)
+	}
+}
+
+func TestObjectPrototype(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript("class Animal { speak() { return 'hi' } }; var base = Animal.prototype; var pet = {}; pet", "")
+	pet, _ := val.AsObject()
+	val, _ = ctx.RunScript("base", "")
+	base, _ := val.AsObject()
+
+	proto, err := pet.GetPrototype()
+	fatalIf(t, err)
+	val, _ = ctx.RunScript("Object.prototype", "")
+	if !proto.SameValue(val) {
+		t.Error("expected Object.prototype")
+	}
+
+	fatalIf(t, pet.SetPrototype(base))
+	val, err = ctx.RunScript("pet.speak()", "")
+	fatalIf(t, err)
+	if val.String() != "hi" {
+		t.Errorf("expected hi, got %s", val.String())
+	}
+	if pet.GetConstructorName() != "Animal" {
+		t.Errorf("expected Animal, got %s", pet.GetConstructorName())
+	}
+
+	fatalIf(t, pet.SetPrototype(v8.Null(ctx.Isolate())))
+	proto, err = pet.GetPrototype()
+	fatalIf(t, err)
+	if !proto.IsNull() {
+		t.Error("expected null prototype")
+	}
+
+	if err := pet.SetPrototype(v8.Undefined(ctx.Isolate())); err == nil {
+		t.Error("expected error for an undefined prototype")
+	}
+	val, _ = ctx.RunScript("Object.preventExtensions({})", "")
+	locked, _ := val.AsObject()
+	if err := locked.SetPrototype(base); err == nil {
+		t.Error("expected error for a non-extensible object")
+	}
+	val, _ = ctx.RunScript("new Proxy({}, { setPrototypeOf() { throw new Error('trap') } })", "")
+	proxy, _ := val.AsObject()
+	if err := proxy.SetPrototype(base); err == nil {
+		t.Error("expected error from the throwing trap")
+	}
+}
+
+func TestObjectSetIntegrityLevel(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	val, _ := ctx.RunScript("var frozen = { a: 1 }; var sealed = { a: 1 }; frozen", "")
+	frozen, _ := val.AsObject()
+	val, _ = ctx.RunScript("sealed", "")
+	sealed, _ := val.AsObject()
+
+	fatalIf(t, frozen.SetIntegrityLevel(v8.Frozen))
+	fatalIf(t, sealed.SetIntegrityLevel(v8.Sealed))
+
+	val, err := ctx.RunScript(`
+		frozen.a = 2; frozen.b = 2; sealed.a = 2; sealed.b = 2; delete sealed.a;
+		[Object.isFrozen(frozen), frozen.a, frozen.b, Object.isSealed(sealed), Object.isFrozen(sealed), sealed.a, sealed.b].join(',')`, "")
+	fatalIf(t, err)
+	if val.String() != "true,1,,true,false,2," {
+		t.Errorf("expected true,1,,true,false,2, got %s", val.String())
+	}
+
+	val, _ = ctx.RunScript("new Proxy({}, { preventExtensions() { throw new Error('trap') } })", "")
+	proxy, _ := val.AsObject()
+	if err := proxy.SetIntegrityLevel(v8.Frozen); err == nil {
+		t.Error("expected error from the throwing trap")
+	}
+}
+
+func TestObjectCreationContext(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx1 := v8.NewContextWithOptions(iso)
+	defer ctx1.Close()
+	ctx2 := v8.NewContextWithOptions(iso)
+	defer ctx2.Close()
+
+	val, _ := ctx2.RunScript("({})", "")
+	obj, _ := val.AsObject()
+	created, err := obj.CreationContext()
+	fatalIf(t, err)
+	if created != ctx2 {
+		t.Error("expected the object to be created in the second context")
+	}
+}
+
 func ExampleObject_global() {
 	iso := v8.NewIsolate()
 	defer iso.Dispose()
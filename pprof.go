@@ -0,0 +1,217 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// pprofProfile builds a profile in the gzipped protocol buffer format read by
+// `go tool pprof`, see https://github.com/google/pprof/blob/main/proto/profile.proto.
+type pprofProfile struct {
+	sampleTypes   []pprofValueType
+	periodType    pprofValueType
+	period        int64
+	timeNanos     int64
+	durationNanos int64
+
+	samples   []pprofSample
+	locations []pprofFrame
+	functions []pprofFunction
+
+	strings     []string
+	stringIDs   map[string]int64
+	locationIDs map[pprofFrame]uint64
+	functionIDs map[pprofFunction]uint64
+}
+
+type pprofValueType struct {
+	typ, unit int64
+}
+
+type pprofSample struct {
+	locationIDs []uint64
+	values      []int64
+}
+
+// pprofFrame is a source position in a function, which is a pprof location.
+type pprofFrame struct {
+	function string
+	file     string
+	line     int64
+	column   int64
+}
+
+type pprofFunction struct {
+	name string
+	file string
+}
+
+func newPprofProfile() *pprofProfile {
+	return &pprofProfile{
+		strings:     []string{""},
+		stringIDs:   map[string]int64{"": 0},
+		locationIDs: make(map[pprofFrame]uint64),
+		functionIDs: make(map[pprofFunction]uint64),
+	}
+}
+
+func (p *pprofProfile) stringID(s string) int64 {
+	if id, ok := p.stringIDs[s]; ok {
+		return id
+	}
+	id := int64(len(p.strings))
+	p.strings = append(p.strings, s)
+	p.stringIDs[s] = id
+	return id
+}
+
+func (p *pprofProfile) valueType(typ, unit string) pprofValueType {
+	return pprofValueType{typ: p.stringID(typ), unit: p.stringID(unit)}
+}
+
+func (p *pprofProfile) addSampleType(typ, unit string) {
+	p.sampleTypes = append(p.sampleTypes, p.valueType(typ, unit))
+}
+
+func (p *pprofProfile) setPeriod(typ, unit string, period int64) {
+	p.periodType = p.valueType(typ, unit)
+	p.period = period
+}
+
+// location returns the id of the location of frame, adding it if needed.
+func (p *pprofProfile) location(frame pprofFrame) uint64 {
+	if id, ok := p.locationIDs[frame]; ok {
+		return id
+	}
+	fn := pprofFunction{name: frame.function, file: frame.file}
+	if _, ok := p.functionIDs[fn]; !ok {
+		p.functions = append(p.functions, fn)
+		p.functionIDs[fn] = uint64(len(p.functions))
+	}
+	p.locations = append(p.locations, frame)
+	id := uint64(len(p.locations))
+	p.locationIDs[frame] = id
+	return id
+}
+
+// addSample adds a sample for the stack of locations, leaf first.
+func (p *pprofProfile) addSample(locationIDs []uint64, values ...int64) {
+	p.samples = append(p.samples, pprofSample{locationIDs: locationIDs, values: values})
+}
+
+func (p *pprofProfile) write(w io.Writer) error {
+	var b protobuf
+	for _, st := range p.sampleTypes {
+		b.message(1, p.encodeValueType(st))
+	}
+	for _, s := range p.samples {
+		var sb protobuf
+		sb.uint64s(1, s.locationIDs)
+		sb.int64s(2, s.values)
+		b.message(2, sb)
+	}
+	for i, frame := range p.locations {
+		var line protobuf
+		line.uint64(1, p.functionIDs[pprofFunction{name: frame.function, file: frame.file}])
+		line.int64(2, frame.line)
+		line.int64(3, frame.column)
+		var lb protobuf
+		lb.uint64(1, uint64(i+1))
+		lb.message(4, line)
+		b.message(4, lb)
+	}
+	for i, fn := range p.functions {
+		var fb protobuf
+		fb.uint64(1, uint64(i+1))
+		fb.int64(2, p.stringID(fn.name))
+		fb.int64(3, p.stringID(fn.name))
+		fb.int64(4, p.stringID(fn.file))
+		b.message(5, fb)
+	}
+	// every string has been interned above, so the table can be written last
+	for _, s := range p.strings {
+		b.string(6, s)
+	}
+	b.int64(9, p.timeNanos)
+	b.int64(10, p.durationNanos)
+	b.message(11, p.encodeValueType(p.periodType))
+	b.int64(12, p.period)
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (p *pprofProfile) encodeValueType(vt pprofValueType) protobuf {
+	var b protobuf
+	b.int64(1, vt.typ)
+	b.int64(2, vt.unit)
+	return b
+}
+
+// protobuf is a minimal protocol buffer encoder for the pprof format.
+type protobuf struct {
+	data []byte
+}
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protobuf) key(tag int, wireType int) {
+	b.varint(uint64(tag)<<3 | uint64(wireType))
+}
+
+func (b *protobuf) uint64(tag int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.key(tag, 0)
+	b.varint(x)
+}
+
+func (b *protobuf) int64(tag int, x int64) {
+	b.uint64(tag, uint64(x))
+}
+
+func (b *protobuf) uint64s(tag int, xs []uint64) {
+	var packed protobuf
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytes(tag, packed.data)
+}
+
+func (b *protobuf) int64s(tag int, xs []int64) {
+	var packed protobuf
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	b.bytes(tag, packed.data)
+}
+
+func (b *protobuf) string(tag int, s string) {
+	b.key(tag, 2)
+	b.varint(uint64(len(s)))
+	b.data = append(b.data, s...)
+}
+
+func (b *protobuf) bytes(tag int, data []byte) {
+	b.key(tag, 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protobuf) message(tag int, m protobuf) {
+	b.bytes(tag, m.data)
+}
@@ -39,6 +39,7 @@ func NewPromiseResolver(ctx *Context) (*PromiseResolver, error) {
 	if ctx == nil {
 		return nil, errors.New("v8go: Context is required")
 	}
+	ctx.iso.checkThread()
 	rtn := C.NewPromiseResolver(ctx.ptr)
 	obj, err := objectResult(ctx.iso, rtn)
 	if err != nil {
@@ -62,17 +63,20 @@ func (r *PromiseResolver) GetPromise() *Promise {
 // Resolve invokes the Promise resolve state with the given value.
 // The Promise state will transition from Pending to Fulfilled.
 func (r *PromiseResolver) Resolve(val Valuer) bool {
+	r.ISO.checkThread()
 	return C.PromiseResolverResolve(r.ptr, val.value().ptr) != 0
 }
 
 // Reject invokes the Promise reject state with the given value.
 // The Promise state will transition from Pending to Rejected.
 func (r *PromiseResolver) Reject(err *Value) bool {
+	r.ISO.checkThread()
 	return C.PromiseResolverReject(r.ptr, err.ptr) != 0
 }
 
 // State returns the current state of the Promise.
 func (p *Promise) State() PromiseState {
+	p.ISO.checkThread()
 	return PromiseState(C.PromiseState(p.ptr))
 }
 
@@ -80,6 +84,7 @@ func (p *Promise) State() PromiseState {
 // NOT be in a Pending state, otherwise may panic. Call promise.State()
 // to validate state before calling for the result.
 func (p *Promise) Result() *Value {
+	p.ISO.checkThread()
 	ptr := C.PromiseResult(p.ptr)
 	val := NewValueStruct(ptr, p.ISO)
 	return val
@@ -89,24 +94,27 @@ func (p *Promise) Result() *Value {
 // The first is invoked when the promise has been fulfilled.
 // The second is invoked when the promise has been rejected.
 // The returned Promise resolves after the callback finishes execution.
+// The callbacks are released once the promise no longer references them.
 //
 // V8 only invokes the callback when processing "microtasks".
 // The default MicrotaskPolicy processes them when the call depth decreases to 0.
 // Call (*Context).PerformMicrotaskCheckpoint to trigger it manually.
 func (p *Promise) Then(cbs ...FunctionCallback) *Promise {
-	var rtn C.RtnValue
-	switch len(cbs) {
-	case 1:
-		cbID := p.ISO.registerCallback(cbs[0])
-		rtn = C.PromiseThen(p.ptr, C.int(cbID))
-	case 2:
-		cbID1 := p.ISO.registerCallback(cbs[0])
-		cbID2 := p.ISO.registerCallback(cbs[1])
-		rtn = C.PromiseThen2(p.ptr, C.int(cbID1), C.int(cbID2))
-
-	default:
+	if len(cbs) < 1 || len(cbs) > 2 {
 		panic("1 or 2 callbacks required")
 	}
+	if !hasExtensions {
+		return p.thenRefs(cbs...)
+	}
+	onFulfilled := p.newReaction(cbs[0])
+	defer p.ISO.BatchMarkCanReleaseInC(onFulfilled.Value)
+	var onRejected C.ValuePtr
+	if len(cbs) == 2 {
+		fn := p.newReaction(cbs[1])
+		defer p.ISO.BatchMarkCanReleaseInC(fn.Value)
+		onRejected = fn.ptr
+	}
+	rtn := C.PromiseThenFunctions(p.ptr, onFulfilled.ptr, onRejected)
 	obj, err := objectResult(p.ISO, rtn)
 	if err != nil {
 		panic(err) // TODO: Return error
@@ -117,11 +125,54 @@ func (p *Promise) Then(cbs ...FunctionCallback) *Promise {
 // Catch invokes the given function if the promise is rejected.
 // See Then for other details.
 func (p *Promise) Catch(cb FunctionCallback) *Promise {
-	cbID := p.ISO.registerCallback(cb)
-	rtn := C.PromiseCatch(p.ptr, C.int(cbID))
+	if !hasExtensions {
+		return p.catchRef(cb)
+	}
+	onRejected := p.newReaction(cb)
+	defer p.ISO.BatchMarkCanReleaseInC(onRejected.Value)
+	rtn := C.PromiseCatchFunction(p.ptr, onRejected.ptr)
+	obj, err := objectResult(p.ISO, rtn)
+	if err != nil {
+		panic(err) // TODO: Return error
+	}
+	return &Promise{obj}
+}
+
+// thenRefs is Then with the older entry points, whose callbacks are only
+// released when the ISO is disposed.
+func (p *Promise) thenRefs(cbs ...FunctionCallback) *Promise {
+	var rtn C.RtnValue
+	if len(cbs) == 1 {
+		rtn = C.PromiseThen(p.ptr, C.int(p.ISO.registerCallback(cbs[0])))
+	} else {
+		rtn = C.PromiseThen2(p.ptr, C.int(p.ISO.registerCallback(cbs[0])), C.int(p.ISO.registerCallback(cbs[1])))
+	}
 	obj, err := objectResult(p.ISO, rtn)
 	if err != nil {
 		panic(err) // TODO: Return error
 	}
 	return &Promise{obj}
 }
+
+// catchRef is Catch with the older entry point, see thenRefs.
+func (p *Promise) catchRef(cb FunctionCallback) *Promise {
+	obj, err := objectResult(p.ISO, C.PromiseCatch(p.ptr, C.int(p.ISO.registerCallback(cb))))
+	if err != nil {
+		panic(err) // TODO: Return error
+	}
+	return &Promise{obj}
+}
+
+// newReaction creates a function in the context of the promise that calls cb,
+// and releases cb once it is garbage collected.
+func (p *Promise) newReaction(cb FunctionCallback) *Function {
+	ctx, err := p.CreationContext()
+	if err != nil {
+		panic(err)
+	}
+	fn, err := NewFunction(ctx, cb)
+	if err != nil {
+		panic(err)
+	}
+	return fn
+}
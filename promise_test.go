@@ -110,6 +110,40 @@ func TestPromiseRejected(t *testing.T) {
 	}
 }
 
+func TestPromiseReleasesCallbacks(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	count := iso.CallbackCount()
+	var calls int
+	fn := func(_ *v8.FunctionCallbackInfo) *v8.Value {
+		calls++
+		return nil
+	}
+	for i := 0; i < 10; i++ {
+		res, err := v8.NewPromiseResolver(ctx)
+		fatalIf(t, err)
+		prom := res.GetPromise()
+		then := prom.Then(fn, fn)
+		catch := prom.Catch(fn)
+		res.Resolve(v8.Undefined(iso))
+		ctx.PerformMicrotaskCheckpoint()
+		iso.BatchMarkCanReleaseInC(res.Value, prom.Value, then.Value, catch.Value)
+	}
+	if calls != 10 {
+		t.Errorf("expected 10 calls, got %d", calls)
+	}
+
+	iso.RequestGarbageCollectionForTesting(v8.FullGarbageCollection)
+	if iso.CallbackCount() != count {
+		t.Errorf("expected the callbacks to be released, got %d callbacks instead of %d", iso.CallbackCount(), count)
+	}
+}
+
 func TestPromiseThenPanic(t *testing.T) {
 	t.Parallel()
 
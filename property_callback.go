@@ -0,0 +1,298 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import "errors"
+
+// PropertyCallbackInfo is the argument that is passed to accessor and
+// interceptor callbacks.
+type PropertyCallbackInfo struct {
+	ctx    *Context
+	this   *Object
+	holder *Object
+}
+
+// Context is the current context that the callback is being executed in.
+func (i *PropertyCallbackInfo) Context() *Context {
+	return i.ctx
+}
+
+// This returns the receiver of the property access, which can be an object
+// inheriting from the object created from the template.
+func (i *PropertyCallbackInfo) This() *Object {
+	return i.this
+}
+
+// Holder returns the object created from the template that holds the
+// accessor or interceptor.
+func (i *PropertyCallbackInfo) Holder() *Object {
+	return i.holder
+}
+
+// AccessorGetter is a callback that returns the value of an accessor property.
+type AccessorGetter func(info *PropertyCallbackInfo) *Value
+
+// AccessorSetter is a callback that is called with the value assigned to an
+// accessor property.
+type AccessorSetter func(info *PropertyCallbackInfo, value *Value)
+
+// NamedPropertyHandler intercepts the accesses to the string-keyed properties
+// of objects created from an ObjectTemplate, see SetNamedPropertyHandler.
+// Any callback can be nil, in which case the access is not intercepted.
+type NamedPropertyHandler struct {
+	// Getter returns the value of the property, or nil to not intercept the
+	// access and look the property up on the object.
+	Getter func(info *PropertyCallbackInfo, name string) *Value
+	// Setter returns true to intercept the assignment of the property, or
+	// false to let it set the property on the object.
+	Setter func(info *PropertyCallbackInfo, name string, value *Value) bool
+	// Query returns the attributes of the property and true if it is
+	// intercepted, as used by `in` and Object.getOwnPropertyDescriptor.
+	Query func(info *PropertyCallbackInfo, name string) (PropertyAttribute, bool)
+	// Deleter returns whether the property was deleted, and true if the
+	// deletion is intercepted.
+	Deleter func(info *PropertyCallbackInfo, name string) (deleted bool, intercepted bool)
+	// Enumerator returns the names of the intercepted properties, as listed by
+	// Object.keys and `for...in`.
+	Enumerator func(info *PropertyCallbackInfo) []string
+}
+
+// IndexedPropertyHandler intercepts the accesses to the indexed properties of
+// objects created from an ObjectTemplate, see SetIndexedPropertyHandler. Any
+// callback can be nil, in which case the access is not intercepted.
+type IndexedPropertyHandler struct {
+	// Getter returns the value at the index, or nil to not intercept the
+	// access and look the property up on the object.
+	Getter func(info *PropertyCallbackInfo, index uint32) *Value
+	// Setter returns true to intercept the assignment at the index, or false
+	// to let it set the property on the object.
+	Setter func(info *PropertyCallbackInfo, index uint32, value *Value) bool
+	// Query returns the attributes of the property at the index and true if it
+	// is intercepted.
+	Query func(info *PropertyCallbackInfo, index uint32) (PropertyAttribute, bool)
+	// Deleter returns whether the property at the index was deleted, and true
+	// if the deletion is intercepted.
+	Deleter func(info *PropertyCallbackInfo, index uint32) (deleted bool, intercepted bool)
+	// Enumerator returns the indices of the intercepted properties.
+	Enumerator func(info *PropertyCallbackInfo) []uint32
+}
+
+type propertyCallbackKind int
+
+const (
+	accessorGetterKind propertyCallbackKind = iota
+	accessorSetterKind
+	namedGetterKind
+	namedSetterKind
+	namedQueryKind
+	namedDeleterKind
+	namedEnumeratorKind
+	indexedGetterKind
+	indexedSetterKind
+	indexedQueryKind
+	indexedDeleterKind
+	indexedEnumeratorKind
+)
+
+// interceptorCallback flags the interceptor callbacks that are set.
+const (
+	interceptorGetter = 1 << iota
+	interceptorSetter
+	interceptorQuery
+	interceptorDeleter
+	interceptorEnumerator
+)
+
+// propertyHandler holds the callbacks registered for an accessor or an
+// interceptor.
+type propertyHandler struct {
+	getter  AccessorGetter
+	setter  AccessorSetter
+	named   *NamedPropertyHandler
+	indexed *IndexedPropertyHandler
+}
+
+func (i *Isolate) registerPropertyHandler(h *propertyHandler) int {
+	i.cbMutex.Lock()
+	defer i.cbMutex.Unlock()
+	i.cbSeq++
+	ref := i.cbSeq
+	i.props[ref] = h
+	return ref
+}
+
+func (i *Isolate) getPropertyHandler(ref int) *propertyHandler {
+	i.cbMutex.RLock()
+	defer i.cbMutex.RUnlock()
+	return i.props[ref]
+}
+
+// SetAccessor adds an accessor property to each instance created by this
+// template, whose value is computed by getter, and assigned through setter.
+// Without a setter the property is read-only.
+func (o *ObjectTemplate) SetAccessor(name string, getter AccessorGetter, setter AccessorSetter, attributes ...PropertyAttribute) error {
+	if len(name) == 0 {
+		return errors.New("v8go: You must provide a valid property name")
+	}
+	if getter == nil {
+		return errors.New("v8go: accessor requires a getter")
+	}
+	var attrs PropertyAttribute
+	for _, a := range attributes {
+		attrs |= a
+	}
+	var hasSetter C.int
+	if setter != nil {
+		hasSetter = 1
+	}
+
+	vname, err := NewValue(o.iso, name)
+	if err != nil {
+		return err
+	}
+	ref := o.iso.registerPropertyHandler(&propertyHandler{getter: getter, setter: setter})
+	C.ObjectTemplateSetAccessor(o.ptr, vname.ptr, C.int(ref), hasSetter, C.int(attrs))
+	return nil
+}
+
+// SetNamedPropertyHandler intercepts the accesses to the string-keyed
+// properties of each instance created by this template. Symbol-keyed
+// properties are not intercepted. Setting a handler replaces the previous one.
+func (o *ObjectTemplate) SetNamedPropertyHandler(handler NamedPropertyHandler) {
+	var callbacks int
+	if handler.Getter != nil {
+		callbacks |= interceptorGetter
+	}
+	if handler.Setter != nil {
+		callbacks |= interceptorSetter
+	}
+	if handler.Query != nil {
+		callbacks |= interceptorQuery
+	}
+	if handler.Deleter != nil {
+		callbacks |= interceptorDeleter
+	}
+	if handler.Enumerator != nil {
+		callbacks |= interceptorEnumerator
+	}
+	ref := o.iso.registerPropertyHandler(&propertyHandler{named: &handler})
+	C.ObjectTemplateSetNamedPropertyHandler(o.ptr, C.int(ref), C.int(callbacks))
+}
+
+// SetIndexedPropertyHandler intercepts the accesses to the indexed properties
+// of each instance created by this template. Setting a handler replaces the
+// previous one.
+func (o *ObjectTemplate) SetIndexedPropertyHandler(handler IndexedPropertyHandler) {
+	var callbacks int
+	if handler.Getter != nil {
+		callbacks |= interceptorGetter
+	}
+	if handler.Setter != nil {
+		callbacks |= interceptorSetter
+	}
+	if handler.Query != nil {
+		callbacks |= interceptorQuery
+	}
+	if handler.Deleter != nil {
+		callbacks |= interceptorDeleter
+	}
+	if handler.Enumerator != nil {
+		callbacks |= interceptorEnumerator
+	}
+	ref := o.iso.registerPropertyHandler(&propertyHandler{indexed: &handler})
+	C.ObjectTemplateSetIndexedPropertyHandler(o.ptr, C.int(ref), C.int(callbacks))
+}
+
+//export goPropertyCallback
+func goPropertyCallback(ctxref int, ref int, args *C.PropertyCallbackArgs) (rtn C.ValuePtr) {
+	ctx := getContext(ctxref)
+	if ctx == nil {
+		// the context was closed, so the access is not intercepted
+		return nil
+	}
+	iso := ctx.iso
+	defer func() {
+		if r := recover(); r != nil {
+			rtn = iso.throwPanic(r).ptr
+		}
+	}()
+	h := iso.getPropertyHandler(ref)
+
+	info := &PropertyCallbackInfo{
+		ctx:    ctx,
+		this:   &Object{NewValueStruct(args.this_ptr, iso)},
+		holder: &Object{NewValueStruct(args.holder_ptr, iso)},
+	}
+	values := []*Value{info.this.Value, info.holder.Value}
+	var name string
+	if args.key_ptr != nil {
+		key := NewValueStruct(args.key_ptr, iso)
+		values = append(values, key)
+		name = key.String()
+	}
+	var value *Value
+	if args.value_ptr != nil {
+		value = NewValueStruct(args.value_ptr, iso)
+		values = append(values, value)
+	}
+	defer iso.BatchMarkCanReleaseInC(values...)
+	index := uint32(args.index)
+
+	var result *Value
+	switch propertyCallbackKind(args.kind) {
+	case accessorGetterKind:
+		result = h.getter(info)
+	case accessorSetterKind:
+		h.setter(info, value)
+	case namedGetterKind:
+		result = h.named.Getter(info, name)
+	case namedSetterKind:
+		if h.named.Setter(info, name, value) {
+			result = newValueBool(iso, true)
+		}
+	case namedQueryKind:
+		if attrs, ok := h.named.Query(info, name); ok {
+			result, _ = NewValue(iso, int32(attrs))
+		}
+	case namedDeleterKind:
+		if deleted, ok := h.named.Deleter(info, name); ok {
+			result = newValueBool(iso, deleted)
+		}
+	case namedEnumeratorKind:
+		if names := h.named.Enumerator(info); names != nil {
+			result, _ = NewValue(iso, names)
+		}
+	case indexedGetterKind:
+		result = h.indexed.Getter(info, index)
+	case indexedSetterKind:
+		if h.indexed.Setter(info, index, value) {
+			result = newValueBool(iso, true)
+		}
+	case indexedQueryKind:
+		if attrs, ok := h.indexed.Query(info, index); ok {
+			result, _ = NewValue(iso, int32(attrs))
+		}
+	case indexedDeleterKind:
+		if deleted, ok := h.indexed.Deleter(info, index); ok {
+			result = newValueBool(iso, deleted)
+		}
+	case indexedEnumeratorKind:
+		if indices := h.indexed.Enumerator(info); indices != nil {
+			result, _ = NewValue(iso, indices)
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	return result.ptr
+}
+
+func newValueBool(iso *Isolate, b bool) *Value {
+	val, _ := NewValue(iso, b)
+	return val
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"sort"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestObjectTemplateSetAccessor(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	hp := int32(100)
+	player := v8.NewObjectTemplate(iso)
+	fatalIf(t, player.SetAccessor("hp", func(info *v8.PropertyCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, hp)
+		return val
+	}, func(info *v8.PropertyCallbackInfo, value *v8.Value) {
+		hp = value.Int32()
+	}))
+	fatalIf(t, player.SetAccessor("name", func(info *v8.PropertyCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, "gopher")
+		return val
+	}, nil, v8.DontEnum))
+	if err := player.SetAccessor("broken", nil, nil); err == nil {
+		t.Error("expected error for a nil getter")
+	}
+	global := v8.NewObjectTemplate(iso)
+	global.Set("player", player)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("player.hp -= 30; player.name = 'x'; player.hp + ':' + player.name + ':' + Object.keys(player)", "accessor.js")
+	fatalIf(t, err)
+	if val.String() != "70:gopher:hp" {
+		t.Errorf("expected 70:gopher:hp, got %s", val.String())
+	}
+	if hp != 70 {
+		t.Errorf("expected the setter to update the Go value, got %d", hp)
+	}
+}
+
+func TestObjectTemplateSetNamedPropertyHandler(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	config := map[string]string{"host": "localhost", "port": "8080"}
+	tmpl := v8.NewObjectTemplate(iso)
+	tmpl.SetNamedPropertyHandler(v8.NamedPropertyHandler{
+		Getter: func(info *v8.PropertyCallbackInfo, name string) *v8.Value {
+			s, ok := config[name]
+			if !ok {
+				return nil
+			}
+			val, _ := v8.NewValue(iso, s)
+			return val
+		},
+		Setter: func(info *v8.PropertyCallbackInfo, name string, value *v8.Value) bool {
+			if name == "local" {
+				return false
+			}
+			config[name] = value.String()
+			return true
+		},
+		Query: func(info *v8.PropertyCallbackInfo, name string) (v8.PropertyAttribute, bool) {
+			_, ok := config[name]
+			return v8.None, ok
+		},
+		Deleter: func(info *v8.PropertyCallbackInfo, name string) (bool, bool) {
+			if _, ok := config[name]; !ok {
+				return false, false
+			}
+			delete(config, name)
+			return true, true
+		},
+		Enumerator: func(info *v8.PropertyCallbackInfo) []string {
+			names := make([]string, 0, len(config))
+			for name := range config {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return names
+		},
+	})
+	global := v8.NewObjectTemplate(iso)
+	global.Set("config", tmpl)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source   string
+		expected string
+	}{
+		{"config.host + ':' + config.port", "localhost:8080"},
+		{"config.user = 'root'; config.local = 1; Object.keys(config).sort().join(',')", "host,local,port,user"},
+		{"['host' in config, 'missing' in config, config.missing].join(',')", "true,false,"},
+		{"delete config.port; 'port' in config", "false"},
+		{"config.local", "1"},
+		{"typeof config.toString", "function"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "named.js")
+		fatalIf(t, err)
+		if val.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.expected, val.String())
+		}
+	}
+	if config["user"] != "root" {
+		t.Errorf("expected the setter to update the Go map, got %v", config)
+	}
+	if _, ok := config["local"]; ok {
+		t.Error("expected a property that is not intercepted to be set on the object")
+	}
+}
+
+func TestObjectTemplateSetIndexedPropertyHandler(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	row := []int32{1, 2, 3}
+	tmpl := v8.NewObjectTemplate(iso)
+	tmpl.SetIndexedPropertyHandler(v8.IndexedPropertyHandler{
+		Getter: func(info *v8.PropertyCallbackInfo, index uint32) *v8.Value {
+			if index >= uint32(len(row)) {
+				return nil
+			}
+			val, _ := v8.NewValue(iso, row[index])
+			return val
+		},
+		Setter: func(info *v8.PropertyCallbackInfo, index uint32, value *v8.Value) bool {
+			if index >= uint32(len(row)) {
+				return false
+			}
+			row[index] = value.Int32()
+			return true
+		},
+		Enumerator: func(info *v8.PropertyCallbackInfo) []uint32 {
+			indices := make([]uint32, len(row))
+			for i := range row {
+				indices[i] = uint32(i)
+			}
+			return indices
+		},
+	})
+	tmpl.Set("length", int32(len(row)), v8.DontEnum)
+	global := v8.NewObjectTemplate(iso)
+	global.Set("row", tmpl)
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("row[1] = 20; row[5] = 6; Array.prototype.join.call(row, ',') + ':' + Object.keys(row).sort() + ':' + row[5]", "indexed.js")
+	fatalIf(t, err)
+	if val.String() != "1,20,3:0,1,2,5:6" {
+		t.Errorf("expected 1,20,3:0,1,2,5:6, got %s", val.String())
+	}
+	if row[1] != 20 {
+		t.Errorf("expected the setter to update the Go slice, got %v", row)
+	}
+}
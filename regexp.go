@@ -0,0 +1,91 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import "errors"
+
+// RegExpFlags are the flags of a RegExp. The flags can be combined with a
+// bitwise or.
+type RegExpFlags int
+
+const (
+	RegExpNone       RegExpFlags = 0
+	RegExpGlobal     RegExpFlags = 1 << 0 // g
+	RegExpIgnoreCase RegExpFlags = 1 << 1 // i
+	RegExpMultiline  RegExpFlags = 1 << 2 // m
+	RegExpSticky     RegExpFlags = 1 << 3 // y
+	RegExpUnicode    RegExpFlags = 1 << 4 // u
+	RegExpDotAll     RegExpFlags = 1 << 5 // s
+	RegExpHasIndices RegExpFlags = 1 << 7 // d
+)
+
+// String returns the flags as written after a RegExp literal, such as "gi".
+func (f RegExpFlags) String() string {
+	var s []byte
+	for _, flag := range [...]struct {
+		flag RegExpFlags
+		c    byte
+	}{
+		{RegExpHasIndices, 'd'},
+		{RegExpGlobal, 'g'},
+		{RegExpIgnoreCase, 'i'},
+		{RegExpMultiline, 'm'},
+		{RegExpDotAll, 's'},
+		{RegExpUnicode, 'u'},
+		{RegExpSticky, 'y'},
+	} {
+		if f&flag.flag != 0 {
+			s = append(s, flag.c)
+		}
+	}
+	return string(s)
+}
+
+// RegExp is a JavaScript regular expression object.
+type RegExp struct {
+	*Object
+}
+
+// NewRegExp creates a RegExp for the pattern, like `new RegExp(pattern, flags)`
+// in JS. A SyntaxError is returned as a JSError for an invalid pattern.
+func NewRegExp(ctx *Context, pattern string, flags RegExpFlags) (*RegExp, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctx.iso.checkThread()
+	vpattern, err := NewValue(ctx.iso, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	rtn := C.NewRegExp(ctx.ptr, vpattern.ptr, C.int(flags))
+	obj, err := objectResult(ctx.iso, rtn)
+	if err != nil {
+		return nil, err
+	}
+	return &RegExp{obj}, nil
+}
+
+// Source returns the pattern of the RegExp.
+func (r *RegExp) Source() string {
+	return NewValueStruct(C.RegExpGetSource(r.ptr), r.ISO).String()
+}
+
+// Flags returns the flags of the RegExp.
+func (r *RegExp) Flags() RegExpFlags {
+	return RegExpFlags(C.RegExpGetFlags(r.ptr))
+}
+
+// AsRegExp will cast the value to the RegExp type. If the value is not a
+// RegExp then an error is returned.
+func (v *Value) AsRegExp() (*RegExp, error) {
+	if !v.IsRegExp() {
+		return nil, errors.New("v8go: value is not a RegExp")
+	}
+	return &RegExp{&Object{v}}, nil
+}
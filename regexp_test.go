@@ -0,0 +1,69 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewRegExp(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	re, err := v8.NewRegExp(ctx, `a(\d+)`, v8.RegExpGlobal|v8.RegExpIgnoreCase)
+	fatalIf(t, err)
+	if !re.IsRegExp() {
+		t.Fatal("expected RegExp")
+	}
+	if re.Source() != `a(\d+)` {
+		t.Errorf("expected source a(\\d+), got %s", re.Source())
+	}
+	if re.Flags() != v8.RegExpGlobal|v8.RegExpIgnoreCase {
+		t.Errorf("expected flags gi, got %s", re.Flags())
+	}
+	fatalIf(t, ctx.Global().Set("re", re))
+	val, err := ctx.RunScript("'a1 A22 b3'.match(re).join(',') + ':' + re.flags", "match.js")
+	fatalIf(t, err)
+	if val.String() != "a1,A22:gi" {
+		t.Errorf("expected a1,A22:gi, got %s", val.String())
+	}
+
+	_, err = v8.NewRegExp(ctx, "(", v8.RegExpNone)
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Errorf("expected JSError for an invalid pattern, got %v", err)
+	}
+	if _, err := v8.NewRegExp(nil, "a", v8.RegExpNone); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+}
+
+func TestValueAsRegExp(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("/x.y/msu", "regexp.js")
+	fatalIf(t, err)
+	re, err := val.AsRegExp()
+	fatalIf(t, err)
+	if re.Source() != "x.y" {
+		t.Errorf("expected source x.y, got %s", re.Source())
+	}
+	if re.Flags().String() != "msu" {
+		t.Errorf("expected flags msu, got %s", re.Flags())
+	}
+	if _, err := v8.Undefined(ctx.Isolate()).AsRegExp(); err == nil {
+		t.Error("expected error for a non RegExp")
+	}
+}
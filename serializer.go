@@ -0,0 +1,277 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// SerializeOptions configures SerializeWithOptions.
+type SerializeOptions struct {
+	// Transfer lists ArrayBuffers whose contents are not written to the
+	// serialized data, like the transfer list of `postMessage` in JS. They are
+	// detached once serialized, which leaves them empty, and their memory is
+	// returned by SerializeWithOptions as BackingStores, in the same order, to
+	// be passed on to DeserializeOptions.Transfer.
+	Transfer []*Value
+	// WriteHostObject, if set, returns the bytes of a host object, that is an
+	// object created from an ObjectTemplate with internal fields. Without it,
+	// serializing a host object fails with a DataCloneError.
+	WriteHostObject func(obj *Object) ([]byte, error)
+}
+
+// DeserializeOptions configures DeserializeWithOptions.
+type DeserializeOptions struct {
+	// Transfer lists the BackingStores returned by SerializeWithOptions for
+	// the buffers transferred by SerializeOptions.Transfer, in the same order.
+	// The transferred buffers are created over them without copying, in any
+	// isolate, and they are released once deserialized. Deserializing data
+	// with transferred buffers fails without them.
+	Transfer []*BackingStore
+	// ReadHostObject, if set, returns the host object read from the bytes
+	// written by SerializeOptions.WriteHostObject.
+	ReadHostObject func(ctx *Context, data []byte) (*Object, error)
+}
+
+// BackingStore is the memory of an ArrayBuffer transferred by
+// SerializeWithOptions. It keeps the memory alive, without copying it, until
+// it is given to DeserializeWithOptions or released.
+type BackingStore struct {
+	mu  sync.Mutex
+	ptr C.BackingStorePtr
+}
+
+func newBackingStore(ptr C.BackingStorePtr) *BackingStore {
+	b := &BackingStore{ptr: ptr}
+	runtime.SetFinalizer(b, (*BackingStore).Release)
+	return b
+}
+
+// ByteLength returns the size of the memory in bytes, or 0 once the
+// BackingStore was deserialized or released.
+func (b *BackingStore) ByteLength() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ptr == nil {
+		return 0
+	}
+	return int(C.BackingStoreByteLength(b.ptr))
+}
+
+// Release frees the memory if it is not used by an ArrayBuffer. The
+// BackingStore cannot be deserialized afterwards.
+func (b *BackingStore) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ptr == nil {
+		return
+	}
+	C.BackingStoreRelease(b.ptr)
+	b.ptr = nil
+	runtime.SetFinalizer(b, nil)
+}
+
+// serializerHooks are the host object hooks of a running Serialize or Deserialize.
+type serializerHooks struct {
+	ctx   *Context
+	write func(obj *Object) ([]byte, error)
+	read  func(ctx *Context, data []byte) (*Object, error)
+}
+
+var serializerMutex sync.Mutex
+var serializerRegistry = make(map[int]*serializerHooks)
+var serializerSeq = 0
+
+func registerSerializerHooks(hooks *serializerHooks) int {
+	serializerMutex.Lock()
+	defer serializerMutex.Unlock()
+	serializerSeq++
+	ref := serializerSeq
+	serializerRegistry[ref] = hooks
+	return ref
+}
+
+func getSerializerHooks(ref int) *serializerHooks {
+	serializerMutex.Lock()
+	defer serializerMutex.Unlock()
+	return serializerRegistry[ref]
+}
+
+func unregisterSerializerHooks(ref int) {
+	serializerMutex.Lock()
+	defer serializerMutex.Unlock()
+	delete(serializerRegistry, ref)
+}
+
+//export goWriteHostObject
+func goWriteHostObject(ref int, objPtr C.ValuePtr, length *C.size_t, errMsg **C.char) (data unsafe.Pointer) {
+	defer func() {
+		if r := recover(); r != nil {
+			*errMsg = moduleCString(panicError(r).Error())
+			data = nil
+		}
+	}()
+	hooks := getSerializerHooks(ref)
+	if hooks == nil || hooks.write == nil {
+		*errMsg = moduleCString("v8go: no WriteHostObject hook to serialize host object")
+		return nil
+	}
+	b, err := hooks.write(&Object{NewValueStruct(objPtr, hooks.ctx.iso)})
+	if err != nil {
+		*errMsg = moduleCString(err.Error())
+		return nil
+	}
+	*length = C.size_t(len(b))
+	// allocate at least one byte, so that empty data is not mistaken for an error
+	return moduleCBytes(append(b, 0))
+}
+
+//export goReadHostObject
+func goReadHostObject(ref int, data unsafe.Pointer, length C.size_t, errMsg **C.char) (rtn C.ValuePtr) {
+	defer func() {
+		if r := recover(); r != nil {
+			*errMsg = moduleCString(panicError(r).Error())
+			rtn = nil
+		}
+	}()
+	hooks := getSerializerHooks(ref)
+	if hooks == nil || hooks.read == nil {
+		*errMsg = moduleCString("v8go: no ReadHostObject hook to deserialize host object")
+		return nil
+	}
+	obj, err := hooks.read(hooks.ctx, C.GoBytes(data, C.int(length)))
+	if err != nil {
+		*errMsg = moduleCString(err.Error())
+		return nil
+	}
+	if obj == nil {
+		*errMsg = moduleCString("v8go: ReadHostObject returned a nil object")
+		return nil
+	}
+	return obj.ptr
+}
+
+func transferPtrs(transfer []*Value) ([]C.ValuePtr, error) {
+	ptrs := make([]C.ValuePtr, len(transfer))
+	for i, buf := range transfer {
+		if buf == nil || !buf.IsArrayBuffer() {
+			return nil, errors.New("v8go: only ArrayBuffers can be transferred")
+		}
+		ptrs[i] = buf.ptr
+	}
+	return ptrs, nil
+}
+
+// Serialize writes val with the structured clone algorithm of V8, as used by
+// `postMessage` and `structuredClone` in browsers. Unlike JSON, it preserves
+// Dates, RegExps, Maps, Sets, BigInts, typed arrays and cyclic references.
+// Functions, symbols and host objects cannot be serialized, and return a
+// JSError for a DataCloneError.
+//
+// The data can be read by Deserialize in any isolate running the same version
+// of V8.
+func Serialize(ctx *Context, val Valuer) ([]byte, error) {
+	data, _, err := SerializeWithOptions(ctx, val, SerializeOptions{})
+	return data, err
+}
+
+// SerializeWithOptions is like Serialize, with options to transfer
+// ArrayBuffers and to serialize host objects. It also returns the
+// BackingStores of the transferred ArrayBuffers, in the order of
+// opts.Transfer.
+func SerializeWithOptions(ctx *Context, val Valuer, opts SerializeOptions) ([]byte, []*BackingStore, error) {
+	if ctx == nil {
+		return nil, nil, errors.New("v8go: Context is required")
+	}
+	if val == nil {
+		return nil, nil, errors.New("v8go: value is required")
+	}
+	ctx.iso.checkThread()
+	transfer, err := transferPtrs(opts.Transfer)
+	if err != nil {
+		return nil, nil, err
+	}
+	var transferPtr *C.ValuePtr
+	var storesPtr *C.BackingStorePtr
+	stores := make([]C.BackingStorePtr, len(transfer))
+	if len(transfer) > 0 {
+		transferPtr = &transfer[0]
+		storesPtr = &stores[0]
+	}
+
+	ref := registerSerializerHooks(&serializerHooks{ctx: ctx, write: opts.WriteHostObject})
+	defer unregisterSerializerHooks(ref)
+
+	rtn := C.Serialize(ctx.ptr, val.value().ptr, transferPtr, C.int(len(transfer)), C.int(ref), storesPtr)
+	if rtn.data == nil {
+		return nil, nil, ctx.iso.executionError(newJSError(ctx.iso, rtn.error))
+	}
+	defer FreeModuleCPtr(rtn.data)
+	var backingStores []*BackingStore
+	for _, ptr := range stores {
+		backingStores = append(backingStores, newBackingStore(ptr))
+	}
+	return C.GoBytes(rtn.data, C.int(rtn.length)), backingStores, nil
+}
+
+// Deserialize reads a value written by Serialize into ctx. Invalid data
+// returns a JSError.
+func Deserialize(ctx *Context, data []byte) (*Value, error) {
+	return DeserializeWithOptions(ctx, data, DeserializeOptions{})
+}
+
+// DeserializeWithOptions is like Deserialize, with options to provide
+// transferred ArrayBuffers and to deserialize host objects.
+func DeserializeWithOptions(ctx *Context, data []byte, opts DeserializeOptions) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("v8go: cannot deserialize empty data")
+	}
+	ctx.iso.checkThread()
+	seen := make(map[*BackingStore]bool, len(opts.Transfer))
+	for _, b := range opts.Transfer {
+		if b == nil || seen[b] {
+			return nil, errors.New("v8go: each transferred BackingStore must be given once")
+		}
+		seen[b] = true
+		b.mu.Lock()
+		defer b.mu.Unlock()
+	}
+	stores := make([]C.BackingStorePtr, len(opts.Transfer))
+	for i, b := range opts.Transfer {
+		if b.ptr == nil {
+			return nil, errors.New("v8go: BackingStore was already deserialized or released")
+		}
+		stores[i] = b.ptr
+	}
+	var storesPtr *C.BackingStorePtr
+	if len(stores) > 0 {
+		storesPtr = &stores[0]
+	}
+
+	ref := registerSerializerHooks(&serializerHooks{ctx: ctx, read: opts.ReadHostObject})
+	defer unregisterSerializerHooks(ref)
+
+	rtn := C.Deserialize(ctx.ptr, unsafe.Pointer(&data[0]), C.size_t(len(data)), storesPtr, C.int(len(stores)), C.int(ref))
+	val, err := valueResult(ctx.iso, rtn)
+	if err != nil {
+		return nil, err
+	}
+	// the transferred buffers now own the memory
+	for _, b := range opts.Transfer {
+		C.BackingStoreRelease(b.ptr)
+		b.ptr = nil
+		runtime.SetFinalizer(b, nil)
+	}
+	return val, nil
+}
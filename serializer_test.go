@@ -0,0 +1,197 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestSerialize(t *testing.T) {
+	t.Parallel()
+
+	src := v8.NewContextWithOptions()
+	defer src.Isolate().Dispose()
+	defer src.Close()
+	dst := v8.NewContextWithOptions()
+	defer dst.Isolate().Dispose()
+	defer dst.Close()
+
+	val, err := src.RunScript(`
+		const v = {
+			date: new Date(0),
+			map: new Map([["k", 1n << 70n]]),
+			set: new Set([1, 2]),
+			re: /a+/g,
+			bytes: new Uint16Array([1, 2, 3]),
+		};
+		v.self = v;
+		v`, "value.js")
+	fatalIf(t, err)
+	data, err := v8.Serialize(src, val)
+	fatalIf(t, err)
+
+	copied, err := v8.Deserialize(dst, data)
+	fatalIf(t, err)
+	fatalIf(t, dst.Global().Set("v", copied))
+	checks := map[string]string{
+		"v.date.getTime()":               "0",
+		"v.map.get('k') === 1n << 70n":   "true",
+		"[...v.set].join(',')":           "1,2",
+		"v.re.source + v.re.flags":       "a+g",
+		"v.bytes instanceof Uint16Array": "true",
+		"v.bytes.join(',')":              "1,2,3",
+		"v.self === v":                   "true",
+	}
+	for script, expected := range checks {
+		res, err := dst.RunScript(script, "check.js")
+		fatalIf(t, err)
+		if res.String() != expected {
+			t.Errorf("%s: expected %q, got %q", script, expected, res.String())
+		}
+	}
+}
+
+func TestSerialize_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fn, err := ctx.RunScript("({ f() {} })", "fn.js")
+	fatalIf(t, err)
+	_, err = v8.Serialize(ctx, fn)
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Errorf("expected JSError for a function, got %v", err)
+	}
+
+	if _, err := v8.Deserialize(ctx, []byte{0xff, 0x0d, 0x6f, 0x22}); err == nil {
+		t.Error("expected error for truncated data")
+	}
+	if _, err := v8.Deserialize(ctx, nil); err == nil {
+		t.Error("expected error for empty data")
+	}
+	if _, err := v8.Serialize(nil, fn); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+	if _, _, err := v8.SerializeWithOptions(ctx, fn, v8.SerializeOptions{Transfer: []*v8.Value{fn}}); err == nil {
+		t.Error("expected error for transferring a non ArrayBuffer")
+	}
+}
+
+func TestSerializeTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	buf, err := ctx.RunScript("new Uint8Array(1024).fill(7).buffer", "buffer.js")
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("buf", buf))
+	val, err := ctx.RunScript("new Uint8Array(buf)[1000] = 9; ({ view: new Uint8Array(buf, 512) })", "view.js")
+	fatalIf(t, err)
+
+	data, stores, err := v8.SerializeWithOptions(ctx, val, v8.SerializeOptions{Transfer: []*v8.Value{buf}})
+	fatalIf(t, err)
+	if len(data) >= 1024 {
+		t.Errorf("expected transferred contents not to be serialized, got %d bytes", len(data))
+	}
+	if n := len(buf.ArrayBufferBytes()); n != 0 {
+		t.Errorf("expected transferred buffer to be detached, got %d bytes", n)
+	}
+	if len(stores) != 1 || stores[0].ByteLength() != 1024 {
+		t.Fatalf("expected the backing store of the transferred buffer, got %v", stores)
+	}
+
+	if _, err := v8.Deserialize(ctx, data); err == nil {
+		t.Error("expected error for deserializing without the transferred buffer")
+	}
+
+	// the memory moves to another isolate without being copied
+	other := v8.NewContextWithOptions()
+	defer other.Isolate().Dispose()
+	defer other.Close()
+	copied, err := v8.DeserializeWithOptions(other, data, v8.DeserializeOptions{Transfer: stores})
+	fatalIf(t, err)
+	fatalIf(t, other.Global().Set("copied", copied))
+	res, err := other.RunScript("copied.view.buffer.byteLength === 1024 && copied.view.length === 512 && copied.view[0] === 7 && copied.view[488] === 9", "check.js")
+	fatalIf(t, err)
+	if !res.Boolean() {
+		t.Error("expected the view to use the transferred memory")
+	}
+
+	if stores[0].ByteLength() != 0 {
+		t.Error("expected the backing store to be released once deserialized")
+	}
+	if _, err := v8.DeserializeWithOptions(other, data, v8.DeserializeOptions{Transfer: stores}); err == nil {
+		t.Error("expected error for deserializing a backing store twice")
+	}
+}
+
+func TestSerializeHostObjects(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	tmpl := v8.NewObjectTemplate(iso)
+	tmpl.SetInternalFieldCount(1)
+	host, err := tmpl.NewInstance(ctx)
+	fatalIf(t, err)
+	fatalIf(t, host.SetInternalField(0, "payload"))
+	wrapper, err := v8.ToJS(ctx, map[string]interface{}{"host": host})
+	fatalIf(t, err)
+
+	if _, err := v8.Serialize(ctx, wrapper); err == nil {
+		t.Error("expected error for a host object without hooks")
+	}
+
+	data, _, err := v8.SerializeWithOptions(ctx, wrapper, v8.SerializeOptions{
+		WriteHostObject: func(obj *v8.Object) ([]byte, error) {
+			return []byte(obj.GetInternalField(0).String()), nil
+		},
+	})
+	fatalIf(t, err)
+
+	var read []byte
+	copied, err := v8.DeserializeWithOptions(ctx, data, v8.DeserializeOptions{
+		ReadHostObject: func(ctx *v8.Context, data []byte) (*v8.Object, error) {
+			read = data
+			obj, err := tmpl.NewInstance(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return obj, obj.SetInternalField(0, string(data))
+		},
+	})
+	fatalIf(t, err)
+	if !bytes.Equal(read, []byte("payload")) {
+		t.Errorf("expected payload, got %q", read)
+	}
+	obj, _ := copied.AsObject()
+	val, err := obj.Get("host")
+	fatalIf(t, err)
+	hostCopy, _ := val.AsObject()
+	if hostCopy.GetInternalField(0).String() != "payload" {
+		t.Errorf("expected payload in the internal field, got %s", hostCopy.GetInternalField(0))
+	}
+
+	_, _, err = v8.SerializeWithOptions(ctx, wrapper, v8.SerializeOptions{
+		WriteHostObject: func(obj *v8.Object) ([]byte, error) {
+			return nil, errors.New("not serializable")
+		},
+	})
+	if err == nil {
+		t.Error("expected the hook error")
+	}
+}
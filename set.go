@@ -0,0 +1,87 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import "errors"
+
+// Set is a JavaScript Set object. Values can be any value, and are compared
+// with the SameValueZero algorithm.
+type Set struct {
+	*Object
+}
+
+// NewSet creates a new, empty Set.
+func NewSet(ctx *Context) (*Set, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctx.iso.checkThread()
+	return &Set{&Object{NewValueStruct(C.NewValueSet(ctx.ptr), ctx.iso)}}, nil
+}
+
+// Add adds the value to the Set. Values can be Go primitives, as supported by
+// Object.Set, or values.
+func (s *Set) Add(val interface{}) error {
+	s.ISO.checkThread()
+	v, err := coerceValue(s.ISO, val)
+	if err != nil {
+		return err
+	}
+	_, err = boolResult(s.ISO, C.SetAdd(s.ptr, v.ptr))
+	return err
+}
+
+// Has returns true if the Set has the value.
+func (s *Set) Has(val interface{}) (bool, error) {
+	s.ISO.checkThread()
+	v, err := coerceValue(s.ISO, val)
+	if err != nil {
+		return false, err
+	}
+	return boolResult(s.ISO, C.SetHas(s.ptr, v.ptr))
+}
+
+// Delete removes the value from the Set, and returns true if it was present.
+func (s *Set) Delete(val interface{}) (bool, error) {
+	s.ISO.checkThread()
+	v, err := coerceValue(s.ISO, val)
+	if err != nil {
+		return false, err
+	}
+	return boolResult(s.ISO, C.SetDelete(s.ptr, v.ptr))
+}
+
+// Size returns the number of values in the Set.
+func (s *Set) Size() int {
+	return int(C.SetSize(s.ptr))
+}
+
+// Values returns the values of the Set in insertion order.
+func (s *Set) Values() ([]*Value, error) {
+	s.ISO.checkThread()
+	arr := &Object{NewValueStruct(C.SetAsArray(s.ptr), s.ISO)}
+	n := uint32(arr.ArrayLen())
+	values := make([]*Value, 0, n)
+	for i := uint32(0); i < n; i++ {
+		val, err := arr.GetIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, val)
+	}
+	return values, nil
+}
+
+// AsSet will cast the value to the Set type. If the value is not a Set then an
+// error is returned.
+func (v *Value) AsSet() (*Set, error) {
+	if !v.IsSet() {
+		return nil, errors.New("v8go: value is not a Set")
+	}
+	return &Set{&Object{v}}, nil
+}
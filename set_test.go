@@ -0,0 +1,75 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	if _, err := v8.NewSet(nil); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+
+	s, err := v8.NewSet(ctx)
+	fatalIf(t, err)
+	if !s.IsSet() {
+		t.Fatal("expected Set")
+	}
+	fatalIf(t, s.Add("a"))
+	fatalIf(t, s.Add(int32(1)))
+	fatalIf(t, s.Add("a"))
+	if s.Size() != 2 {
+		t.Errorf("expected size 2, got %d", s.Size())
+	}
+	if ok, _ := s.Has(int32(1)); !ok {
+		t.Error("expected 1 in the Set")
+	}
+	if ok, _ := s.Has("1"); ok {
+		t.Error("expected values to be compared without conversion")
+	}
+	if ok, _ := s.Delete("a"); !ok {
+		t.Error("expected a to be deleted")
+	}
+
+	fatalIf(t, ctx.Global().Set("s", s))
+	val, err := ctx.RunScript("s.add('b'); s.size", "set.js")
+	fatalIf(t, err)
+	if val.Int32() != 2 {
+		t.Errorf("expected size 2 from JS, got %d", val.Int32())
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("new Set(['x', 2, 'x', true])", "set.js")
+	fatalIf(t, err)
+	s, err := val.AsSet()
+	fatalIf(t, err)
+	values, err := s.Values()
+	fatalIf(t, err)
+	if len(values) != 3 {
+		t.Fatalf("expected 3 values, got %d", len(values))
+	}
+	if values[0].String() != "x" || values[1].Int32() != 2 || !values[2].Boolean() {
+		t.Errorf("unexpected values %v", values)
+	}
+	if _, err := v8.Undefined(ctx.Isolate()).AsSet(); err == nil {
+		t.Error("expected error for a non Set")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"math"
+	"unsafe"
+)
+
+// NewStringFromBytes creates a string from UTF-8 encoded bytes. Unlike C
+// strings, b may contain NUL bytes; invalid UTF-8 sequences are replaced with
+// U+FFFD.
+func NewStringFromBytes(iso *Isolate, b []byte) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new string: Isolate cannot be <nil>")
+	}
+	if len(b) > math.MaxInt32 {
+		return nil, errors.New("v8go: failed to create new string: string is too long")
+	}
+	iso.checkThread()
+	var ptr *C.char
+	if len(b) > 0 {
+		ptr = (*C.char)(unsafe.Pointer(&b[0]))
+	}
+	return valueResult(iso, C.NewValueStringFromBytes(iso.ptr, ptr, C.int(len(b))))
+}
+
+// NewStringFromUTF16 creates a string from UTF-16 code units, as used by JS
+// strings. Unpaired surrogates are kept as is.
+func NewStringFromUTF16(iso *Isolate, s []uint16) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new string: Isolate cannot be <nil>")
+	}
+	if len(s) > math.MaxInt32 {
+		return nil, errors.New("v8go: failed to create new string: string is too long")
+	}
+	iso.checkThread()
+	var ptr *C.uint16_t
+	if len(s) > 0 {
+		ptr = (*C.uint16_t)(unsafe.Pointer(&s[0]))
+	}
+	return valueResult(iso, C.NewValueStringFromUTF16(iso.ptr, ptr, C.int(len(s))))
+}
+
+// NewExternalOneByteString creates a string backed by a copy of data outside
+// of the V8 heap, which V8 does not copy again into the heap. This is meant for
+// large immutable strings such as script sources. Each byte is one Latin-1
+// character, so data should be ASCII for the string to match its UTF-8
+// interpretation. The copy is freed once V8 no longer uses it, at which point
+// release, if not nil, is called. release may be called on any goroutine.
+// Use NewExternalOneByteStringFromPointer to avoid the copy, or to share one
+// source between many strings and isolates.
+func NewExternalOneByteString(iso *Isolate, data []byte, release func()) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new string: Isolate cannot be <nil>")
+	}
+	iso.checkThread()
+	ptr, ref := copyBackingStore(data, release)
+	return valueResult(iso, C.NewValueExternalOneByteString(iso.ptr, (*C.char)(ptr), C.size_t(len(data)), C.int(ref)))
+}
+
+// NewExternalOneByteStringFromPointer is like NewExternalOneByteString, for
+// length bytes of memory at ptr that is not managed by Go, such as memory from
+// C.malloc or a memory-mapped file. The memory is not copied, so the same
+// source can back strings in many isolates, e.g. by counting references in
+// release. The memory must stay valid and unchanged until release is called,
+// once V8 no longer uses the string; release may be called on any goroutine.
+func NewExternalOneByteStringFromPointer(iso *Isolate, ptr unsafe.Pointer, length int, release func()) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new string: Isolate cannot be <nil>")
+	}
+	if ptr == nil && length > 0 {
+		return nil, errors.New("v8go: failed to create new string: pointer cannot be <nil>")
+	}
+	if length < 0 {
+		return nil, errors.New("v8go: failed to create new string: length cannot be negative")
+	}
+	iso.checkThread()
+	ref := registerBackingStore(release)
+	return valueResult(iso, C.NewValueExternalOneByteString(iso.ptr, (*C.char)(ptr), C.size_t(length), C.int(ref)))
+}
+
+// StringBytes performs the equivalent of `String(value)` in JS and returns the
+// result as UTF-8 encoded bytes, including any NUL characters. Lone surrogates
+// are replaced with U+FFFD.
+func (v *Value) StringBytes() []byte {
+	s := C.ValueToStringBytes(v.ptr)
+	defer FreeModuleCPtr(s.data)
+	return C.GoBytes(s.data, s.length)
+}
+
+// UTF16 performs the equivalent of `String(value)` in JS and returns the
+// result as UTF-16 code units, exactly as stored by JS.
+func (v *Value) UTF16() []uint16 {
+	s := C.ValueToUTF16(v.ptr)
+	defer FreeModuleCPtr(s.data)
+	if s.length == 0 {
+		return []uint16{}
+	}
+	units := make([]uint16, int(s.length))
+	copy(units, (*[1 << 28]uint16)(s.data)[:s.length:s.length])
+	return units
+}
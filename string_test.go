@@ -0,0 +1,254 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewStringFromBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	if _, err := v8.NewStringFromBytes(nil, []byte("a")); err == nil {
+		t.Error("expected error for a nil Isolate")
+	}
+
+	b := []byte("a\x00bé")
+	val, err := v8.NewStringFromBytes(iso, b)
+	fatalIf(t, err)
+	if !val.IsString() {
+		t.Fatal("expected string")
+	}
+	fatalIf(t, ctx.Global().Set("s", val))
+	length, err := ctx.RunScript("s.length", "length.js")
+	fatalIf(t, err)
+	if length.Int32() != 4 {
+		t.Errorf("expected length 4, got %d", length.Int32())
+	}
+	if got := val.StringBytes(); !bytes.Equal(got, b) {
+		t.Errorf("expected %q, got %q", b, got)
+	}
+	if got := val.String(); got != string(b) {
+		t.Errorf("expected %q, got %q", b, got)
+	}
+
+	empty, err := v8.NewStringFromBytes(iso, nil)
+	fatalIf(t, err)
+	if empty.String() != "" || len(empty.StringBytes()) != 0 {
+		t.Errorf("expected empty string, got %q", empty.String())
+	}
+
+	val, err = v8.NewValue(iso, "x\x00y")
+	fatalIf(t, err)
+	if val.String() != "x\x00y" {
+		t.Errorf("expected NewValue to keep NUL bytes, got %q", val.String())
+	}
+}
+
+func TestNewStringFromUTF16(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	units := utf16.Encode([]rune("hé\U0001F600\x00"))
+	val, err := v8.NewStringFromUTF16(iso, units)
+	fatalIf(t, err)
+	if got := val.UTF16(); !reflect.DeepEqual(got, units) {
+		t.Errorf("expected %v, got %v", units, got)
+	}
+	if val.String() != "hé\U0001F600\x00" {
+		t.Errorf("unexpected string %q", val.String())
+	}
+
+	// a lone surrogate is kept by UTF16 but cannot be encoded as UTF-8
+	lone, err := ctx.RunScript("'a\\ud800'", "lone.js")
+	fatalIf(t, err)
+	if got := lone.UTF16(); !reflect.DeepEqual(got, []uint16{'a', 0xd800}) {
+		t.Errorf("expected lone surrogate, got %v", got)
+	}
+	if got := lone.String(); got != "a\uFFFD" {
+		t.Errorf("expected replacement character, got %q", got)
+	}
+
+	if got := v8.Undefined(iso).UTF16(); !reflect.DeepEqual(got, utf16.Encode([]rune("undefined"))) {
+		t.Errorf("expected undefined, got %v", got)
+	}
+}
+
+func TestNewExternalOneByteString(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+
+	source := bytes.Repeat([]byte("var x = 1;\n"), 1024)
+	source = append(source, "x + 1"...)
+	released := make(chan struct{}, 1)
+	val, err := v8.NewExternalOneByteString(iso, source, func() { released <- struct{}{} })
+	fatalIf(t, err)
+	if !val.IsString() {
+		t.Fatal("expected string")
+	}
+	if got := val.StringBytes(); !bytes.Equal(got, source) {
+		t.Error("expected the external string contents")
+	}
+	// the string is backed by a copy of the Go slice
+	source[0] = '/'
+	if got := val.StringBytes(); got[0] != 'v' {
+		t.Errorf("expected the string to be unchanged, got %q", got[0])
+	}
+	fatalIf(t, ctx.Global().Set("src", val))
+	res, err := ctx.RunScript("eval(src)", "eval.js")
+	fatalIf(t, err)
+	if res.Int32() != 2 {
+		t.Errorf("expected 2, got %d", res.Int32())
+	}
+
+	ctx.Close()
+	iso.Dispose()
+	select {
+	case <-released:
+	default:
+		t.Error("expected release to be called once the isolate is disposed")
+	}
+}
+
+func TestNewExternalOneByteStringFromPointer(t *testing.T) {
+	t.Parallel()
+
+	// the memory of an ArrayBuffer is outside of the Go heap, and outlives the strings below
+	owner := v8.NewIsolate()
+	defer owner.Dispose()
+	if _, err := v8.NewExternalOneByteStringFromPointer(owner, nil, 8, nil); err == nil {
+		t.Error("expected error for a nil pointer")
+	}
+	source := []byte("'shared' + 1")
+	buf, err := v8.NewArrayBuffer(owner, source, nil)
+	fatalIf(t, err)
+	data := buf.ArrayBufferBytes()
+
+	var refs int32 = 2
+	released := make(chan struct{})
+	release := func() {
+		if atomic.AddInt32(&refs, -1) == 0 {
+			close(released)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		ctx := v8.NewContextWithOptions()
+		iso := ctx.Isolate()
+		val, err := v8.NewExternalOneByteStringFromPointer(iso, unsafe.Pointer(&data[0]), len(data), release)
+		fatalIf(t, err)
+		fatalIf(t, ctx.Global().Set("src", val))
+		res, err := ctx.RunScript("eval(src)", "eval.js")
+		fatalIf(t, err)
+		if res.String() != "shared1" {
+			t.Errorf("expected shared1, got %s", res.String())
+		}
+		ctx.Close()
+		iso.Dispose()
+	}
+	select {
+	case <-released:
+	default:
+		t.Error("expected release to be called for each string once the isolates are disposed")
+	}
+}
+
+func TestNulNames(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("a\x00b", "template"))
+	fatalIf(t, global.SetAccessor("c\x00d", func(info *v8.PropertyCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, "accessor")
+		return val
+	}, nil))
+	ctx := v8.NewContextWithOptions(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("[globalThis['a\\0b'], globalThis['c\\0d'], 'a' in globalThis, 'c' in globalThis].join()", "names.js")
+	fatalIf(t, err)
+	if val.String() != "template,accessor,false,false" {
+		t.Errorf("expected the template names not to be truncated, got %s", val.String())
+	}
+
+	sym, err := v8.NewSymbol(iso, "x\x00y")
+	fatalIf(t, err)
+	if sym.Description() != "x\x00y" {
+		t.Errorf("expected the symbol description not to be truncated, got %q", sym.Description())
+	}
+	re, err := v8.NewRegExp(ctx, "a\x00b", 0)
+	fatalIf(t, err)
+	if re.Source() != "a\x00b" {
+		t.Errorf("expected the pattern not to be truncated, got %q", re.Source())
+	}
+}
+
+func TestObjectNulKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	obj := ctx.Global()
+	fatalIf(t, obj.Set("a\x00b", "nul"))
+	if obj.Has("a") {
+		t.Error("expected key not to be truncated")
+	}
+	if !obj.Has("a\x00b") {
+		t.Error("expected key with a NUL byte")
+	}
+	val, err := obj.Get("a\x00b")
+	fatalIf(t, err)
+	if val.String() != "nul" {
+		t.Errorf("expected nul, got %s", val.String())
+	}
+	val, err = ctx.RunScript("globalThis['a\\0b']", "key.js")
+	fatalIf(t, err)
+	if val.String() != "nul" {
+		t.Errorf("expected the property to be visible from JS, got %s", val.String())
+	}
+	if !obj.HasOwn("a\x00b") || obj.HasOwn("a") {
+		t.Error("expected HasOwn not to truncate the key")
+	}
+	desc, err := obj.GetOwnPropertyDescriptor("a\x00b")
+	fatalIf(t, err)
+	if desc == nil || desc.Value.String() != "nul" {
+		t.Errorf("expected the descriptor of the key with a NUL byte, got %+v", desc)
+	}
+	if !obj.Delete("a\x00b") || obj.Has("a\x00b") {
+		t.Error("expected key with a NUL byte to be deleted")
+	}
+
+	fatalIf(t, obj.DefineOwnProperty("c\x00d", "defined", v8.ReadOnly))
+	fatalIf(t, obj.SetAccessorProperty("e\x00f", func(info *v8.FunctionCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(info.Isolate(), "accessor")
+		return val
+	}, nil))
+	val, err = ctx.RunScript("[globalThis['c\\0d'], globalThis['e\\0f'], 'c' in globalThis, 'e' in globalThis].join()", "define.js")
+	fatalIf(t, err)
+	if val.String() != "defined,accessor,false,false" {
+		t.Errorf("expected the defined keys not to be truncated, got %s", val.String())
+	}
+}
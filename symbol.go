@@ -0,0 +1,95 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import "errors"
+
+// Symbol is a JavaScript symbol (ECMA-262, 4.3.23), usable as a property key
+// with Object.SetSymbol, GetSymbol and HasSymbol.
+type Symbol struct {
+	*Value
+}
+
+// wellKnownSymbol values are the kinds understood by SymbolWellKnown in C.
+type wellKnownSymbol int
+
+const (
+	symbolIterator wellKnownSymbol = iota
+	symbolAsyncIterator
+	symbolToStringTag
+	symbolHasInstance
+	symbolToPrimitive
+)
+
+// NewSymbol creates a new unique symbol with the given description, like
+// `Symbol(description)` in JS.
+func NewSymbol(iso *Isolate, description string) (*Symbol, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: failed to create new Symbol: Isolate cannot be <nil>")
+	}
+	iso.checkThread()
+	desc, err := NewValue(iso, description)
+	if err != nil {
+		return nil, err
+	}
+	return &Symbol{NewValueStruct(C.NewValueSymbol(iso.ptr, desc.ptr), iso)}, nil
+}
+
+func newWellKnownSymbol(iso *Isolate, kind wellKnownSymbol) *Symbol {
+	return &Symbol{NewValueStruct(C.SymbolWellKnown(iso.ptr, C.int(kind)), iso)}
+}
+
+// SymbolIterator returns the well-known `Symbol.iterator`, which makes an
+// object iterable with for..of.
+func SymbolIterator(iso *Isolate) *Symbol {
+	return newWellKnownSymbol(iso, symbolIterator)
+}
+
+// SymbolAsyncIterator returns the well-known `Symbol.asyncIterator`, which
+// makes an object iterable with for await..of.
+func SymbolAsyncIterator(iso *Isolate) *Symbol {
+	return newWellKnownSymbol(iso, symbolAsyncIterator)
+}
+
+// SymbolToStringTag returns the well-known `Symbol.toStringTag`, which sets
+// the tag returned by Object.prototype.toString.
+func SymbolToStringTag(iso *Isolate) *Symbol {
+	return newWellKnownSymbol(iso, symbolToStringTag)
+}
+
+// SymbolHasInstance returns the well-known `Symbol.hasInstance`, which
+// customizes instanceof.
+func SymbolHasInstance(iso *Isolate) *Symbol {
+	return newWellKnownSymbol(iso, symbolHasInstance)
+}
+
+// SymbolToPrimitive returns the well-known `Symbol.toPrimitive`, which
+// customizes the conversion of an object to a primitive value.
+func SymbolToPrimitive(iso *Isolate) *Symbol {
+	return newWellKnownSymbol(iso, symbolToPrimitive)
+}
+
+// Description returns the description of the symbol, or an empty string if
+// it has none.
+func (s *Symbol) Description() string {
+	s.ISO.checkThread()
+	desc := NewValueStruct(C.SymbolDescription(s.ptr), s.ISO)
+	if desc.IsUndefined() {
+		return ""
+	}
+	return desc.String()
+}
+
+// AsSymbol will cast the value to the Symbol type. If the value is not a
+// Symbol then an error is returned.
+func (v *Value) AsSymbol() (*Symbol, error) {
+	if !v.IsSymbol() {
+		return nil, errors.New("v8go: value is not a Symbol")
+	}
+	return &Symbol{v}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "gitee.com/hasika/v8go"
+)
+
+func TestNewSymbol(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	if _, err := v8.NewSymbol(nil, "nil"); err == nil {
+		t.Error("expected error for a nil Isolate")
+	}
+
+	sym, err := v8.NewSymbol(iso, "tag")
+	fatalIf(t, err)
+	if !sym.IsSymbol() {
+		t.Fatal("expected Symbol")
+	}
+	if sym.Description() != "tag" {
+		t.Errorf("expected description tag, got %q", sym.Description())
+	}
+	other, _ := v8.NewSymbol(iso, "tag")
+	if sym.SameValue(other.Value) {
+		t.Error("expected symbols to be unique")
+	}
+
+	val, err := ctx.RunScript("Symbol()", "")
+	fatalIf(t, err)
+	anon, err := val.AsSymbol()
+	fatalIf(t, err)
+	if anon.Description() != "" {
+		t.Errorf("expected empty description, got %q", anon.Description())
+	}
+	if _, err := v8.Undefined(iso).AsSymbol(); err == nil {
+		t.Error("expected error for a non Symbol")
+	}
+}
+
+func TestWellKnownSymbols(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	tests := map[string]*v8.Symbol{
+		"Symbol.iterator":      v8.SymbolIterator(iso),
+		"Symbol.asyncIterator": v8.SymbolAsyncIterator(iso),
+		"Symbol.toStringTag":   v8.SymbolToStringTag(iso),
+		"Symbol.hasInstance":   v8.SymbolHasInstance(iso),
+		"Symbol.toPrimitive":   v8.SymbolToPrimitive(iso),
+	}
+	for source, sym := range tests {
+		val, err := ctx.RunScript(source, "")
+		fatalIf(t, err)
+		if !sym.SameValue(val) {
+			t.Errorf("expected %s", source)
+		}
+	}
+}
+
+func TestObjectSymbolKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	sym, _ := v8.NewSymbol(iso, "secret")
+	obj := ctx.Global()
+	fatalIf(t, obj.SetSymbol(sym, "hidden"))
+	if !obj.HasSymbol(sym) {
+		t.Error("expected symbol-keyed property")
+	}
+	val, err := obj.GetSymbol(sym)
+	fatalIf(t, err)
+	if val.String() != "hidden" {
+		t.Errorf("expected hidden, got %s", val.String())
+	}
+	if obj.Has("secret") {
+		t.Error("expected no string-keyed property")
+	}
+	if !obj.DeleteSymbol(sym) || obj.HasSymbol(sym) {
+		t.Error("expected symbol-keyed property to be deleted")
+	}
+	if err := obj.SetSymbol(nil, "x"); err == nil {
+		t.Error("expected error for a nil Symbol")
+	}
+
+	fatalIf(t, obj.SetSymbol(v8.SymbolToStringTag(iso), "GoCollection"))
+	val, err = ctx.RunScript("Object.prototype.toString.call(globalThis)", "")
+	fatalIf(t, err)
+	if val.String() != "[object GoCollection]" {
+		t.Errorf("expected [object GoCollection], got %s", val.String())
+	}
+}
+
+func TestObjectSymbolIterator(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	items := []string{"a", "b", "c"}
+	type result struct {
+		Value interface{} `js:"value"`
+		Done  bool        `js:"done"`
+	}
+	iterator := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		i := 0
+		next := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+			if i >= len(items) {
+				val, _ := v8.ToJS(ctx, result{Done: true})
+				return val
+			}
+			i++
+			val, _ := v8.ToJS(ctx, result{Value: items[i-1]})
+			return val
+		})
+		val, _ := v8.ToJS(ctx, map[string]interface{}{"next": next.GetFunction(ctx)})
+		return val
+	})
+
+	collection, err := v8.ToJS(ctx, map[string]int{})
+	fatalIf(t, err)
+	obj, _ := collection.AsObject()
+	fatalIf(t, obj.SetSymbol(v8.SymbolIterator(iso), iterator.GetFunction(ctx)))
+	fatalIf(t, ctx.Global().Set("collection", obj))
+
+	val, err := ctx.RunScript("const seen = []; for (const item of collection) seen.push(item); seen.join(',')", "")
+	fatalIf(t, err)
+	if val.String() != "a,b,c" {
+		t.Errorf("expected a,b,c, got %s", val.String())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 	"unsafe"
 )
 
@@ -29,35 +30,67 @@ type template struct {
 // If the value passed is a Go supported primitive (string, int32, uint32, int64, uint64, float64, big.Int)
 // then a value will be created and set as the value property.
 func (t *template) Set(name string, val interface{}, attributes ...PropertyAttribute) error {
-	cname := C.CString(name)
-	defer FreeCPtr(unsafe.Pointer(cname))
 	var attrs PropertyAttribute
 	for _, a := range attributes {
 		attrs |= a
 	}
 
+	var valPtr C.ValuePtr
+	var tmplPtr C.TemplatePtr
 	switch v := val.(type) {
 	case string, int32, uint32, int64, uint64, float64, bool, *big.Int:
 		newVal, err := NewValue(t.iso, v)
 		if err != nil {
 			return fmt.Errorf("v8go: unable to create new value: %v", err)
 		}
-		C.TemplateSetValue(t.ptr, cname, newVal.ptr, C.int(attrs))
+		valPtr = newVal.ptr
 	case *ObjectTemplate:
-		C.TemplateSetTemplate(t.ptr, cname, v.ptr, C.int(attrs))
+		tmplPtr = v.ptr
 	case *FunctionTemplate:
-		C.TemplateSetTemplate(t.ptr, cname, v.ptr, C.int(attrs))
+		tmplPtr = v.ptr
 	case *Value:
 		if v.IsObject() || v.IsExternal() {
 			return errors.New("v8go: unsupported property: value type must be a primitive or use a template")
 		}
-		C.TemplateSetValue(t.ptr, cname, v.ptr, C.int(attrs))
+		valPtr = v.ptr
 	default:
 		return fmt.Errorf("v8go: unsupported property type `%T`, must be one of string, int32, uint32, int64, uint64, float64, *big.Int, *v8go.Value, *v8go.ObjectTemplate or *v8go.FunctionTemplate", v)
 	}
+
+	if vname, err := nulKey(t.iso, name); err != nil {
+		return err
+	} else if vname != nil {
+		if tmplPtr != nil {
+			C.TemplateSetTemplateKey(t.ptr, vname.ptr, tmplPtr, C.int(attrs))
+		} else {
+			C.TemplateSetValueKey(t.ptr, vname.ptr, valPtr, C.int(attrs))
+		}
+		return nil
+	}
+	cname := C.CString(name)
+	defer FreeCPtr(unsafe.Pointer(cname))
+	if tmplPtr != nil {
+		C.TemplateSetTemplate(t.ptr, cname, tmplPtr, C.int(attrs))
+	} else {
+		C.TemplateSetValue(t.ptr, cname, valPtr, C.int(attrs))
+	}
 	return nil
 }
 
+// newTemplate wraps a template returned by V8, such as the instance template of a
+// FunctionTemplate, so that its wrapper is freed when the isolate is disposed.
+func newTemplate(iso *Isolate, ptr C.TemplatePtr) *template {
+	tmpl := &template{
+		ptr:  ptr,
+		iso:  iso,
+		Name: time.Now().String(),
+	}
+	iso.templateLock.Lock()
+	defer iso.templateLock.Unlock()
+	iso.templates = append(iso.templates, tmpl)
+	return tmpl
+}
+
 func (t *template) finalizer() {
 	// Using v8::PersistentBase::Reset() wouldn't be thread-safe to do from
 	// this finalizer goroutine so just free the wrapper and let the template
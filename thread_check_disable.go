@@ -0,0 +1,10 @@
+//go:build !checkV8thread
+// +build !checkV8thread
+
+package v8go
+
+// CheckThread reports whether v8go was built with the checkV8thread tag, which
+// makes the API panic when an Isolate that has an executor, see Isolate.Run,
+// is used off the executor thread. Isolates that never used Run or Post are
+// not checked.
+var CheckThread = false
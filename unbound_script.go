@@ -7,7 +7,10 @@ package v8go
 // #include <stdlib.h>
 // #include "v8go.h"
 import "C"
-import "unsafe"
+import (
+	"context"
+	"unsafe"
+)
 
 type UnboundScript struct {
 	ptr C.UnboundScriptPtr
@@ -19,6 +22,7 @@ type UnboundScript struct {
 // was compiled in, Run will panic.
 // If an error occurs, it will be of type `JSError`.
 func (u *UnboundScript) Run(ctx *Context) (*Value, error) {
+	u.iso.checkThread()
 	if ctx.Isolate() != u.iso {
 		panic("attempted to run unbound script in a context that belongs to a different ISO")
 	}
@@ -26,6 +30,15 @@ func (u *UnboundScript) Run(ctx *Context) (*Value, error) {
 	return valueResult(ctx.iso, rtn)
 }
 
+// RunContext runs the unbound script like Run, but terminates the execution when
+// goCtx is done. The error is ErrDeadlineExceeded if the deadline of goCtx passed,
+// ErrExecutionTerminated if goCtx was canceled and `JSError` otherwise.
+func (u *UnboundScript) RunContext(goCtx context.Context, ctx *Context) (*Value, error) {
+	return u.iso.runContext(goCtx, func() (*Value, error) {
+		return u.Run(ctx)
+	})
+}
+
 // Create a code cache from the unbound script.
 func (u *UnboundScript) CreateCodeCache() *CompilerCachedData {
 	rtn := C.UnboundScriptCreateCodeCache(u.iso.ptr, u.ptr)
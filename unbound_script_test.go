@@ -5,7 +5,10 @@
 package v8go_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	v8 "gitee.com/hasika/v8go"
 )
@@ -45,3 +48,21 @@ func TestUnboundScriptRun_OnlyInTheSameIsolate(t *testing.T) {
 		t.Error("expected panic running unbound script in a context belonging to a different isolate")
 	}
 }
+
+func TestUnboundScriptRunContext(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContextWithOptions(iso)
+	defer ctx.Close()
+
+	us, err := iso.CompileUnboundScript("while (true) {}", "forever.js", v8.CompileOptions{})
+	fatalIf(t, err)
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := us.RunContext(deadline, ctx); !errors.Is(err, v8.ErrDeadlineExceeded) {
+		t.Fatalf("expected ErrDeadlineExceeded, got %v", err)
+	}
+}
@@ -12,6 +12,18 @@
 #define V8GO_EXPORT
 #endif
 
+// V8GO_EXT marks the entry points that are newer than the prebuilt libraries in
+// lib/. On ELF platforms Go code sees them as weak references, which are NULL
+// when the library does not export them, so that programs using only the older
+// entry points still link; V8GoHasExtensions reports whether they are available.
+// Mach-O and PE cannot link missing weak references from a static or import
+// library, so there the library must be rebuilt from this header.
+#if !defined(__cplusplus) && defined(__ELF__)
+#define V8GO_EXT V8GO_EXPORT __attribute__((weak))
+#else
+#define V8GO_EXT V8GO_EXPORT
+#endif
+
 #ifdef __cplusplus
 
 #include "libplatform/libplatform.h"
@@ -23,6 +35,8 @@ typedef v8::Isolate *IsolatePtr;
 typedef v8::CpuProfiler *CpuProfilerPtr;
 typedef v8::CpuProfile *CpuProfilePtr;
 typedef const v8::CpuProfileNode *CpuProfileNodePtr;
+typedef v8::HeapProfiler *HeapProfilerPtr;
+typedef v8::AllocationProfile *AllocationProfilePtr;
 typedef v8::ScriptCompiler::CachedData *ScriptCompilerCachedDataPtr;
 
 extern "C" {
@@ -40,6 +54,12 @@ typedef v8CpuProfile* CpuProfilePtr;
 typedef struct v8CpuProfileNode v8CpuProfileNode;
 typedef const v8CpuProfileNode* CpuProfileNodePtr;
 
+typedef struct v8HeapProfiler v8HeapProfiler;
+typedef v8HeapProfiler* HeapProfilerPtr;
+
+typedef struct v8AllocationProfile v8AllocationProfile;
+typedef v8AllocationProfile* AllocationProfilePtr;
+
 typedef struct v8ScriptCompilerCachedData v8ScriptCompilerCachedData;
 typedef const v8ScriptCompilerCachedData* ScriptCompilerCachedDataPtr;
 #endif
@@ -56,11 +76,13 @@ typedef struct m_ctx m_ctx;
 typedef struct m_value m_value;
 typedef struct m_template m_template;
 typedef struct m_unboundScript m_unboundScript;
+typedef struct m_backingStore m_backingStore;
 
 typedef m_ctx *ContextPtr;
 typedef m_value *ValuePtr;
 typedef m_template *TemplatePtr;
 typedef m_unboundScript *UnboundScriptPtr;
+typedef m_backingStore *BackingStorePtr;
 
 typedef struct {
     const char *msg;
@@ -91,6 +113,18 @@ typedef struct {
     IsolatePtr iso;
 } CPUProfiler;
 
+typedef struct {
+    int mode;
+    unsigned int maxSamples;
+    int samplingIntervalUs;
+    int recordSamples;
+} CPUProfilingOptions;
+
+typedef struct {
+    int line;
+    unsigned int hitCount;
+} CPUProfileLineTick;
+
 typedef struct CPUProfileNode {
     CpuProfileNodePtr ptr;
     const char *scriptResourceName;
@@ -109,16 +143,78 @@ typedef struct {
     int64_t endTime;
 } CPUProfile;
 
+typedef struct {
+    HeapProfilerPtr ptr;
+    IsolatePtr iso;
+} HeapProfiler;
+
+typedef struct {
+    size_t size;
+    unsigned int count;
+} AllocationProfileAllocation;
+
+typedef struct AllocationProfileNode {
+    const char *scriptResourceName;
+    const char *functionName;
+    int scriptId;
+    int lineNumber;
+    int columnNumber;
+    int allocationsCount;
+    AllocationProfileAllocation *allocations;
+    int childrenCount;
+    struct AllocationProfileNode **children;
+} AllocationProfileNode;
+
+typedef struct {
+    AllocationProfilePtr ptr;
+    AllocationProfileNode *root;
+} AllocationProfile;
+
 typedef struct {
     ValuePtr value;
     RtnError error;
 } RtnValue;
 
+// PropertyCallbackArgs are the arguments of an accessor or interceptor callback.
+typedef struct {
+    int kind;           // see the propertyCallbackKind constants in Go
+    ValuePtr this_ptr;
+    ValuePtr holder_ptr;
+    ValuePtr key_ptr;   // property name of named callbacks, NULL otherwise
+    uint32_t index;     // property index of indexed callbacks
+    ValuePtr value_ptr; // assigned value of setters, NULL otherwise
+} PropertyCallbackArgs;
+
+typedef struct {
+    void *data;
+    size_t byteLength;
+} ArrayBufferContents;
+
 typedef struct {
     const char *string;
     RtnError error;
 } RtnString;
 
+// StringContents is the content of a string copied out of V8, data must be
+// freed with FreeModuleCPtr. length is in bytes for UTF-8 and in code units
+// for UTF-16.
+typedef struct {
+    void *data;
+    int length;
+} StringContents;
+
+typedef struct {
+    int value;
+    RtnError error;
+} RtnBool;
+
+// RtnBytes holds bytes allocated by V8go, data must be freed with FreeModuleCPtr.
+typedef struct {
+    void *data;
+    size_t length;
+    RtnError error;
+} RtnBytes;
+
 typedef struct {
     size_t total_heap_size;
     size_t total_heap_size_executable;
@@ -133,33 +229,120 @@ typedef struct {
     size_t number_of_detached_contexts;
 } IsolateHStatistics;
 
+typedef struct {
+    size_t max_old_generation_size_in_bytes;
+    size_t max_young_generation_size_in_bytes;
+    size_t initial_old_generation_size_in_bytes;
+    size_t stack_size_in_bytes;
+} IsolateOptions;
+
 typedef struct {
     const uint64_t *word_array;
     int word_count;
     int sign_bit;
 } ValueBigInt;
 
-
+// The function callback receives the context ref, the callback ref, the values this
+// followed by the arguments, and the number of arguments.
 extern V8GO_EXPORT void
 InitV8Go(m_ctx *(*getGoContextFuncEntry)(int), ValuePtr (*goFunctionCallbackEntry)(int, int, ValuePtr *, int));
 extern V8GO_EXPORT void Init();
 
 extern V8GO_EXPORT void CloseV8();
 
+// The near heap limit callback receives the isolate ref along with the current and
+// initial heap limits and returns the new limit. When it terminates execution, it
+// returns a limit raised just enough for the termination to unwind instead of
+// aborting the process.
+extern V8GO_EXT void InitV8GoHeapLimitCallback(size_t (*goNearHeapLimitCallbackEntry)(int, size_t, size_t));
+
+// The heap snapshot callback receives the writer ref and the next chunk of the
+// serialized snapshot, and returns 0 to abort the serialization.
+extern V8GO_EXT void InitV8GoHeapSnapshotCallback(int (*goHeapSnapshotWriteEntry)(int, const char *, int));
+
+// The backing store release callback receives the ref given to NewArrayBuffer once
+// V8 no longer uses the memory. It may be called on any thread.
+extern V8GO_EXT void InitV8GoBackingStoreReleaseCallback(void (*goBackingStoreReleaseEntry)(int));
+
+// The host object callbacks receive the hooks ref given to Serialize or Deserialize.
+// The write callback returns the bytes of a host object with their length, and the
+// read callback returns the object read from bytes. On failure both return NULL and
+// set an error message, which is thrown as a DataCloneError. The bytes and the error
+// message are allocated with mallocV8GoPtr and freed by the caller.
+extern V8GO_EXT void InitV8GoSerializerCallbacks(void *(*goWriteHostObjectEntry)(int, ValuePtr, size_t *, char **),
+                                                   ValuePtr (*goReadHostObjectEntry)(int, void *, size_t, char **));
+
+// The property callback receives the context ref, the ref given to ObjectTemplateSetAccessor
+// or to an interceptor, and the callback arguments. It returns NULL when the property is
+// not intercepted, and otherwise the value of a getter, true for a setter, the attributes
+// as an Integer for a query, whether the property was deleted as a Boolean for a deleter,
+// and an Array of the property names or indices for an enumerator.
+extern V8GO_EXT void InitV8GoPropertyCallback(ValuePtr (*goPropertyCallbackEntry)(int, int, PropertyCallbackArgs *));
+
+// The weak callback receives the ref given to ObjectSetWeak once the object is
+// garbage collected.
+extern V8GO_EXT void InitV8GoWeakCallback(void (*goWeakCallbackEntry)(int));
+
+// The function callback info entry is called instead of goFunctionCallbackEntry by the
+// functions of NewFunctionTemplateWithInfo and NewFunction. It receives the values this,
+// holder, new.target (undefined unless called as a constructor) followed by the arguments.
+extern V8GO_EXT void InitV8GoFunctionCallbackInfo(ValuePtr (*goFunctionCallbackInfoEntry)(int, int, ValuePtr *, int));
+
 extern V8GO_EXPORT IsolatePtr NewIsolate(int ref);
 
+// NewIsolateWithOptions creates an isolate with the given resource constraints. Its
+// near heap limit callback is the one given to InitV8GoHeapLimitCallback, and the
+// initial heap limit is restored with AutomaticallyRestoreInitialHeapLimit once the
+// heap shrinks after the callback raised it.
+extern V8GO_EXT IsolatePtr NewIsolateWithOptions(int ref, IsolateOptions options);
+
 extern V8GO_EXPORT void IsolatePerformMicrotaskCheckpoint(IsolatePtr ptr);
 
+extern V8GO_EXT void IsolateEnqueueMicrotask(IsolatePtr ptr, ValuePtr fn_ptr);
+
 extern V8GO_EXPORT void IsolateDispose(IsolatePtr ptr);
 
 extern V8GO_EXPORT void IsolateTerminateExecution(IsolatePtr ptr);
 
+extern V8GO_EXT void IsolateCancelTerminateExecution(IsolatePtr ptr);
+
 extern V8GO_EXPORT int IsolateIsExecutionTerminating(IsolatePtr ptr);
 
 extern V8GO_EXPORT IsolateHStatistics IsolationGetHeapStatistics(IsolatePtr ptr);
 
+// Takes a heap snapshot and streams it in the DevTools .heapsnapshot JSON format
+// to the writer identified by writer_ref. The go_roots values are added to the
+// snapshot as children of a "(Go roots)" node, with the edges named after
+// go_root_names. Returns 0 if the writer aborted the serialization.
+extern V8GO_EXT int IsolateTakeHeapSnapshot(IsolatePtr iso_ptr,
+                                               int writer_ref,
+                                               ValuePtr *go_roots,
+                                               const char **go_root_names,
+                                               int go_root_count);
+
+extern V8GO_EXT void IsolateLowMemoryNotification(IsolatePtr ptr);
+
+extern V8GO_EXT void IsolateMemoryPressureNotification(IsolatePtr ptr, int level);
+
+// idle_time_in_seconds is relative to now; the absolute deadline is computed
+// from the platform's monotonic clock.
+extern V8GO_EXT int IsolateIdleNotificationDeadline(IsolatePtr ptr, double idle_time_in_seconds);
+
+extern V8GO_EXT int64_t IsolateAdjustAmountOfExternalAllocatedMemory(IsolatePtr ptr, int64_t change_in_bytes);
+
+extern V8GO_EXT void IsolateRequestGarbageCollectionForTesting(IsolatePtr ptr, int type);
+
 extern V8GO_EXPORT ValuePtr IsolateThrowException(IsolatePtr iso, ValuePtr value);
 
+// IsolateTakeException returns the value thrown by the last call on the isolate that
+// failed with a RtnError, and clears it. It returns NULL if the error was not caused
+// by a JS exception, such as a terminated execution.
+extern V8GO_EXT ValuePtr IsolateTakeException(IsolatePtr iso_ptr);
+
+// NewValueError creates an Error object of the given kind, see the errorKind
+// constants in Go.
+extern V8GO_EXT ValuePtr NewValueError(IsolatePtr iso_ptr, int kind, const char *message);
+
 extern V8GO_EXPORT RtnUnboundScript IsolateCompileUnboundScript(IsolatePtr iso_ptr,
                                                                 const char *source,
                                                                 const char *origin,
@@ -176,15 +359,66 @@ extern V8GO_EXPORT RtnValue UnboundScriptRun(ContextPtr ctx_ptr, UnboundScriptPt
 
 extern V8GO_EXPORT CPUProfiler *NewCPUProfiler(IsolatePtr iso_ptr);
 
+extern V8GO_EXT CPUProfiler *NewCPUProfilerWithNamingMode(IsolatePtr iso_ptr,
+                                                             int naming_mode);
+
 extern V8GO_EXPORT void CPUProfilerDispose(CPUProfiler *ptr);
 
+extern V8GO_EXT void CPUProfilerSetSamplingInterval(CPUProfiler *ptr, int interval_us);
+
+extern V8GO_EXT void CPUProfilerSetUsePreciseSampling(CPUProfiler *ptr, int use_precise_sampling);
+
 extern V8GO_EXPORT void CPUProfilerStartProfiling(CPUProfiler *ptr, const char *title);
 
+extern V8GO_EXT void CPUProfilerStartProfilingWithOptions(CPUProfiler *ptr,
+                                                             const char *title,
+                                                             CPUProfilingOptions options);
+
 extern V8GO_EXPORT CPUProfile *CPUProfilerStopProfiling(CPUProfiler *ptr,
                                                         const char *title);
 
 extern V8GO_EXPORT void CPUProfileDelete(CPUProfile *ptr);
 
+extern V8GO_EXT int CPUProfileGetSamplesCount(CpuProfilePtr ptr);
+
+// CPUProfileGetSamples copies the node ids and the timestamps, in microseconds, of the
+// first count samples of the profile, see CPUProfileGetSamplesCount.
+extern V8GO_EXT void CPUProfileGetSamples(CpuProfilePtr ptr,
+                                             unsigned int *node_ids,
+                                             int64_t *timestamps,
+                                             int count);
+
+extern V8GO_EXT unsigned int CPUProfileNodeGetNodeId(CpuProfileNodePtr ptr);
+
+extern V8GO_EXT int CPUProfileNodeGetScriptId(CpuProfileNodePtr ptr);
+
+extern V8GO_EXT unsigned int CPUProfileNodeGetHitCount(CpuProfileNodePtr ptr);
+
+// CPUProfileNodeGetBailoutReason returns a string owned by V8, which must not be freed.
+extern V8GO_EXT const char *CPUProfileNodeGetBailoutReason(CpuProfileNodePtr ptr);
+
+extern V8GO_EXT unsigned int CPUProfileNodeGetHitLineCount(CpuProfileNodePtr ptr);
+
+// CPUProfileNodeGetLineTicks copies up to length line ticks of the node into ticks, see
+// CPUProfileNodeGetHitLineCount, and returns 0 if length is too small.
+extern V8GO_EXT int CPUProfileNodeGetLineTicks(CpuProfileNodePtr ptr,
+                                                  CPUProfileLineTick *ticks,
+                                                  unsigned int length);
+
+extern V8GO_EXT HeapProfiler *NewHeapProfiler(IsolatePtr iso_ptr);
+
+extern V8GO_EXT void HeapProfilerDispose(HeapProfiler *ptr);
+
+extern V8GO_EXT int HeapProfilerStartSamplingHeapProfiler(HeapProfiler *ptr,
+                                                             uint64_t sample_interval,
+                                                             int stack_depth);
+
+extern V8GO_EXT void HeapProfilerStopSamplingHeapProfiler(HeapProfiler *ptr);
+
+extern V8GO_EXT AllocationProfile *HeapProfilerGetAllocationProfile(HeapProfiler *ptr);
+
+extern V8GO_EXT void AllocationProfileDelete(AllocationProfile *ptr);
+
 extern V8GO_EXPORT ContextPtr NewContext(IsolatePtr iso_ptr,
                                          TemplatePtr global_template_ptr,
                                          int ref);
@@ -213,6 +447,12 @@ extern V8GO_EXPORT void TemplateSetTemplate(TemplatePtr ptr,
                                             TemplatePtr obj_ptr,
                                             int attributes);
 
+// TemplateSetValueKey and TemplateSetTemplateKey are like TemplateSetValue and
+// TemplateSetTemplate, with a string value as the name, which may contain NUL bytes.
+extern V8GO_EXT void TemplateSetValueKey(TemplatePtr ptr, ValuePtr name_ptr, ValuePtr val_ptr, int attributes);
+
+extern V8GO_EXT void TemplateSetTemplateKey(TemplatePtr ptr, ValuePtr name_ptr, TemplatePtr obj_ptr, int attributes);
+
 extern V8GO_EXPORT TemplatePtr NewObjectTemplate(IsolatePtr iso_ptr);
 
 extern V8GO_EXPORT RtnValue ObjectTemplateNewInstance(TemplatePtr ptr, ContextPtr ctx_ptr);
@@ -222,11 +462,41 @@ extern V8GO_EXPORT void ObjectTemplateSetInternalFieldCount(TemplatePtr ptr,
 
 extern V8GO_EXPORT int ObjectTemplateInternalFieldCount(TemplatePtr ptr);
 
+extern V8GO_EXT void ObjectTemplateSetAccessor(TemplatePtr ptr, ValuePtr name_ptr, int ref, int has_setter, int attributes);
+
+// callbacks is a bit mask of the interceptor callbacks that are set, see the
+// interceptorCallback constants in Go. Symbol-keyed properties are not intercepted.
+extern V8GO_EXT void ObjectTemplateSetNamedPropertyHandler(TemplatePtr ptr, int ref, int callbacks);
+
+extern V8GO_EXT void ObjectTemplateSetIndexedPropertyHandler(TemplatePtr ptr, int ref, int callbacks);
+
 extern V8GO_EXPORT TemplatePtr NewFunctionTemplate(IsolatePtr iso_ptr, int callback_ref);
 
+// NewFunctionTemplateWithInfo creates a function template like NewFunctionTemplate, whose
+// functions call goFunctionCallbackInfo.
+extern V8GO_EXT TemplatePtr NewFunctionTemplateWithInfo(IsolatePtr iso_ptr, int callback_ref);
+
+// NewFunction creates a function without a template, that calls goFunctionCallbackInfo
+// with callback_ref like the functions of NewFunctionTemplateWithInfo.
+extern V8GO_EXT RtnValue NewFunction(ContextPtr ctx_ptr, int callback_ref);
+
 extern V8GO_EXPORT RtnValue FunctionTemplateGetFunction(TemplatePtr ptr,
                                                         ContextPtr ctx_ptr);
 
+extern V8GO_EXT TemplatePtr FunctionTemplateInstanceTemplate(TemplatePtr ptr);
+
+extern V8GO_EXT TemplatePtr FunctionTemplatePrototypeTemplate(TemplatePtr ptr);
+
+extern V8GO_EXT void FunctionTemplateSetClassName(TemplatePtr ptr, ValuePtr name_ptr);
+
+extern V8GO_EXT void FunctionTemplateSetLength(TemplatePtr ptr, int length);
+
+extern V8GO_EXT void FunctionTemplateInherit(TemplatePtr ptr, TemplatePtr parent_ptr);
+
+extern V8GO_EXT void FunctionTemplateReadOnlyPrototype(TemplatePtr ptr);
+
+extern V8GO_EXT void FunctionTemplateRemovePrototype(TemplatePtr ptr);
+
 extern V8GO_EXPORT ValuePtr NewValueNull(IsolatePtr iso_ptr);
 
 extern V8GO_EXPORT ValuePtr NewValueUndefined(IsolatePtr iso_ptr);
@@ -239,6 +509,15 @@ extern V8GO_EXPORT ValuePtr NewValueIntegerFromUnsigned(IsolatePtr iso_ptr, uint
 
 extern V8GO_EXPORT RtnValue NewValueString(IsolatePtr iso_ptr, const char *v);
 
+extern V8GO_EXT RtnValue NewValueStringFromBytes(IsolatePtr iso_ptr, const char *data, int length);
+
+extern V8GO_EXT RtnValue NewValueStringFromUTF16(IsolatePtr iso_ptr, const uint16_t *data, int length);
+
+// NewValueExternalOneByteString creates a string over data without copying it. The data
+// must not be managed by Go, goBackingStoreRelease is called with release_ref from the
+// Dispose of the string resource once V8 no longer uses it.
+extern V8GO_EXT RtnValue NewValueExternalOneByteString(IsolatePtr iso_ptr, const char *data, size_t length, int release_ref);
+
 extern V8GO_EXPORT ValuePtr NewValueBoolean(IsolatePtr iso_ptr, int v);
 
 extern V8GO_EXPORT ValuePtr NewValueNumber(IsolatePtr iso_ptr, double v);
@@ -252,8 +531,90 @@ extern V8GO_EXPORT RtnValue NewValueBigIntFromWords(IsolatePtr iso_ptr,
                                                     int word_count,
                                                     const uint64_t *words);
 
+extern V8GO_EXT ValuePtr NewValueObject(ContextPtr ctx_ptr);
+
+extern V8GO_EXT RtnValue NewValueDate(ContextPtr ctx_ptr, double time_ms);
+
+extern V8GO_EXT ValuePtr NewValueUint8ArrayCopy(IsolatePtr iso_ptr, const uint8_t *data, size_t length);
+
+// NewArrayBuffer creates an ArrayBuffer over the given memory without copying it. The
+// memory must not be managed by Go, and must stay valid until the backing store release
+// callback is called with release_ref.
+extern V8GO_EXT ValuePtr NewArrayBuffer(IsolatePtr iso_ptr, void *data, size_t length, int release_ref);
+
+// NewTypedArray creates a typed array of the given kind, see the TypedArrayKind
+// constants in Go, over length elements of buffer from byte_offset.
+extern V8GO_EXT RtnValue NewTypedArray(ValuePtr buffer_ptr, int kind, size_t byte_offset, size_t length);
+
+// ValueArrayBufferContents returns the memory of an ArrayBuffer, or the range viewed
+// by an ArrayBufferView, and a NULL data for other values.
+extern V8GO_EXT ArrayBufferContents ValueArrayBufferContents(ValuePtr ptr);
+
+extern V8GO_EXT RtnValue NewRegExp(ContextPtr ctx_ptr, ValuePtr pattern_ptr, int flags);
+
+// RegExpGetSource returns the pattern of the RegExp as a string value.
+extern V8GO_EXT ValuePtr RegExpGetSource(ValuePtr ptr);
+
+extern V8GO_EXT int RegExpGetFlags(ValuePtr ptr);
+
+extern V8GO_EXT ValuePtr NewValueMap(ContextPtr ctx_ptr);
+
+extern V8GO_EXT RtnBool MapSet(ValuePtr ptr, ValuePtr key_ptr, ValuePtr val_ptr);
+
+extern V8GO_EXT RtnValue MapGet(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT RtnBool MapHas(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT RtnBool MapDelete(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT size_t MapSize(ValuePtr ptr);
+
+// MapAsArray returns an Array of the entries of the map, as key, value, key, value...
+extern V8GO_EXT ValuePtr MapAsArray(ValuePtr ptr);
+
+extern V8GO_EXT ValuePtr NewValueSet(ContextPtr ctx_ptr);
+
+extern V8GO_EXT RtnBool SetAdd(ValuePtr ptr, ValuePtr val_ptr);
+
+extern V8GO_EXT RtnBool SetHas(ValuePtr ptr, ValuePtr val_ptr);
+
+extern V8GO_EXT RtnBool SetDelete(ValuePtr ptr, ValuePtr val_ptr);
+
+extern V8GO_EXT size_t SetSize(ValuePtr ptr);
+
+extern V8GO_EXT ValuePtr SetAsArray(ValuePtr ptr);
+
+// Serialize writes val with a ValueSerializer. The contents of the ArrayBuffers in
+// transfer are not written: their backing stores are set in stores, by index, and the
+// buffers are detached once serialized. A hooks_ref of 0 disables host objects.
+extern V8GO_EXT RtnBytes Serialize(ContextPtr ctx_ptr, ValuePtr val_ptr, ValuePtr transfer[], int transfer_count, int hooks_ref, BackingStorePtr stores[]);
+
+// Deserialize reads a value written by Serialize, transferred ArrayBuffers are
+// created over the backing stores in stores, by index.
+extern V8GO_EXT RtnValue Deserialize(ContextPtr ctx_ptr, const void *data, size_t length, BackingStorePtr stores[], int store_count, int hooks_ref);
+
+extern V8GO_EXT size_t BackingStoreByteLength(BackingStorePtr ptr);
+
+// BackingStoreRelease drops the reference to the backing store, whose memory is freed
+// once no ArrayBuffer uses it either.
+extern V8GO_EXT void BackingStoreRelease(BackingStorePtr ptr);
+
+extern V8GO_EXT ValuePtr NewValueSymbol(IsolatePtr iso_ptr, ValuePtr description_ptr);
+
+// SymbolWellKnown returns a well-known symbol, see the wellKnownSymbol constants in Go.
+extern V8GO_EXT ValuePtr SymbolWellKnown(IsolatePtr iso_ptr, int kind);
+
+// SymbolDescription returns the description of a symbol, a string or undefined.
+extern V8GO_EXT ValuePtr SymbolDescription(ValuePtr ptr);
+
 extern V8GO_EXPORT const char *ValueToString(ValuePtr ptr);
 
+extern V8GO_EXT StringContents ValueToStringBytes(ValuePtr ptr);
+
+extern V8GO_EXT StringContents ValueToUTF16(ValuePtr ptr);
+
+extern V8GO_EXT double ValueToDateTime(ValuePtr ptr);
+
 extern V8GO_EXPORT const uint32_t *ValueToArrayIndex(ValuePtr ptr);
 
 extern V8GO_EXPORT int ValueToBoolean(ValuePtr ptr);
@@ -278,6 +639,8 @@ extern V8GO_EXPORT  RtnValue ValueToObject(ValuePtr ptr);
 
 extern V8GO_EXPORT int ValueSameValue(ValuePtr ptr, ValuePtr otherPtr);
 
+extern V8GO_EXT RtnBool ValueInstanceOf(ContextPtr ctx_ptr, ValuePtr ptr, ValuePtr ctor_ptr);
+
 extern V8GO_EXPORT int ValueIsUndefined(ValuePtr ptr);
 
 extern V8GO_EXPORT int ValueIsNull(ValuePtr ptr);
@@ -394,6 +757,13 @@ extern V8GO_EXPORT void ObjectSetIdx(ValuePtr ptr, uint32_t idx, ValuePtr val_pt
 
 extern V8GO_EXPORT int ObjectSetInternalField(ValuePtr ptr, int idx, ValuePtr val_ptr);
 
+// ObjectSetWeak calls goWeakCallback with ref once the object is garbage collected.
+extern V8GO_EXT void ObjectSetWeak(ValuePtr ptr, int ref);
+
+// ObjectGetWeak returns the object given ref by ObjectSetWeak as a value of the context,
+// or NULL once it was garbage collected.
+extern V8GO_EXT ValuePtr ObjectGetWeak(ContextPtr ctx_ptr, int ref);
+
 extern V8GO_EXPORT int ObjectInternalFieldCount(ValuePtr ptr);
 
 extern V8GO_EXPORT RtnValue ObjectGet(ValuePtr ptr, const char *key);
@@ -410,6 +780,57 @@ extern V8GO_EXPORT int ObjectDelete(ValuePtr ptr, const char *key);
 
 extern V8GO_EXPORT int ObjectDeleteIdx(ValuePtr ptr, uint32_t idx);
 
+extern V8GO_EXT void ObjectSetValueKey(ValuePtr ptr, ValuePtr key_ptr, ValuePtr val_ptr);
+
+extern V8GO_EXT RtnValue ObjectGetValueKey(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT int ObjectHasValueKey(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT int ObjectDeleteValueKey(ValuePtr ptr, ValuePtr key_ptr);
+
+extern V8GO_EXT RtnValue ObjectKeys(ValuePtr ptr);
+
+extern V8GO_EXT RtnValue ObjectGetPrototype(ValuePtr ptr);
+
+extern V8GO_EXT RtnBool ObjectSetPrototype(ValuePtr ptr, ValuePtr proto_ptr);
+
+// ObjectSetIntegrityLevel freezes (level 0) or seals (level 1) the object, like
+// v8::IntegrityLevel.
+extern V8GO_EXT RtnBool ObjectSetIntegrityLevel(ValuePtr ptr, int level);
+
+extern V8GO_EXT const char *ObjectGetConstructorName(ValuePtr ptr);
+
+extern V8GO_EXT int ObjectGetIdentityHash(ValuePtr ptr);
+
+// ObjectGetCreationContextRef returns the ref of the context the object was created
+// in, or 0 if it was not created in a v8go context.
+extern V8GO_EXT int ObjectGetCreationContextRef(ValuePtr ptr);
+
+// ObjectDefineOwnProperty defines a data property with the v8::PropertyAttribute bits
+// in attributes. The value is 0 if the property could not be redefined.
+extern V8GO_EXT RtnBool ObjectDefineOwnProperty(ValuePtr ptr,
+                                                   ValuePtr key_ptr,
+                                                   ValuePtr val_ptr,
+                                                   int attributes);
+
+// ObjectSetAccessorProperty defines an accessor property with the given getter and
+// setter functions; a NULL function leaves the getter or setter undefined.
+extern V8GO_EXT RtnBool ObjectSetAccessorProperty(ValuePtr ptr,
+                                                     ValuePtr key_ptr,
+                                                     ValuePtr getter_ptr,
+                                                     ValuePtr setter_ptr,
+                                                     int attributes);
+
+// ObjectGetOwnPropertyNames returns an Array of the own property keys matching the
+// v8::PropertyFilter bits in filter, with integer indices converted to strings.
+extern V8GO_EXT RtnValue ObjectGetOwnPropertyNames(ValuePtr ptr, int filter);
+
+extern V8GO_EXT int ObjectHasOwnProperty(ValuePtr ptr, ValuePtr key_ptr);
+
+// ObjectGetOwnPropertyDescriptor returns the descriptor object of an own property, as
+// returned by Object.getOwnPropertyDescriptor, or undefined.
+extern V8GO_EXT RtnValue ObjectGetOwnPropertyDescriptor(ValuePtr ptr, ValuePtr key_ptr);
+
 extern V8GO_EXPORT RtnValue NewPromiseResolver(ContextPtr ctx_ptr);
 
 extern V8GO_EXPORT ValuePtr PromiseResolverGetPromise(ValuePtr ptr);
@@ -426,6 +847,13 @@ extern V8GO_EXPORT RtnValue PromiseThen2(ValuePtr ptr, int on_fulfilled_ref, int
 
 extern V8GO_EXPORT RtnValue PromiseCatch(ValuePtr ptr, int callback_ref);
 
+// PromiseThenFunctions returns the promise derived by calling then with the functions,
+// on_rejected being NULL when only on_fulfilled is given. Unlike PromiseThen, the Go
+// callbacks of the functions are released once they are garbage collected.
+extern V8GO_EXT RtnValue PromiseThenFunctions(ValuePtr ptr, ValuePtr on_fulfilled, ValuePtr on_rejected);
+
+extern V8GO_EXT RtnValue PromiseCatchFunction(ValuePtr ptr, ValuePtr on_rejected);
+
 extern V8GO_EXPORT ValuePtr PromiseResult(ValuePtr ptr);
 
 extern V8GO_EXPORT RtnValue FunctionCall(ValuePtr ptr,
@@ -454,6 +882,10 @@ extern V8GO_EXPORT bool InspectorTick(RawInspectorClientPtr ptr);
 
 extern V8GO_EXPORT bool InspectorAlive(RawInspectorClientPtr ptr);
 
+// mallocV8GoPtr allocates memory with the allocator of the library, for memory
+// handed to the library that it frees with freeV8GoPtr.
+extern V8GO_EXT void *mallocV8GoPtr(size_t size);
+
 extern V8GO_EXPORT void freeV8GoPtr(void *p);
 
 extern V8GO_EXPORT void deleteRecordValuePtr(ValuePtr p);
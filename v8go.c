@@ -1,6 +1,35 @@
 #include "v8go.h"
 #include "_cgo_export.h"
 
+#ifdef _WIN32
+#include <windows.h>
+#else
+#include <pthread.h>
+#endif
+
 void InitV8GoCallBack() {
     InitV8Go(goContext, goFunctionCallback);
+    if (!V8GoHasExtensions()) {
+        return;
+    }
+    InitV8GoHeapLimitCallback(goNearHeapLimitCallback);
+    InitV8GoHeapSnapshotCallback(goHeapSnapshotWrite);
+    InitV8GoBackingStoreReleaseCallback(goBackingStoreRelease);
+    InitV8GoSerializerCallbacks(goWriteHostObject, goReadHostObject);
+    InitV8GoWeakCallback(goWeakCallback);
+    InitV8GoPropertyCallback(goPropertyCallback);
+    InitV8GoFunctionCallbackInfo(goFunctionCallbackInfo);
+}
+
+int V8GoHasExtensions() {
+    // the V8GO_EXT entry points are built and shipped together
+    return InitV8GoFunctionCallbackInfo != NULL && NewFunctionTemplateWithInfo != NULL;
+}
+
+unsigned long long V8GoCurrentThreadID() {
+#ifdef _WIN32
+    return (unsigned long long)GetCurrentThreadId();
+#else
+    return (unsigned long long)(uintptr_t)pthread_self();
+#endif
 }
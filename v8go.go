@@ -15,6 +15,11 @@ import (
 	"unsafe"
 )
 
+// hasExtensions reports whether the linked libv8_export exports the entry points
+// marked V8GO_EXT in v8_export.h, which the prebuilt libraries in lib/ predate.
+// Without them, APIs that existed before fall back to the older entry points.
+var hasExtensions = C.V8GoHasExtensions() != 0
+
 // Version returns the version of the V8 Engine with the -v8go suffix
 func Version() string {
 	ccharptr := C.Version()
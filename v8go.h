@@ -10,6 +10,12 @@ extern "C"
 
 void InitV8GoCallBack();
 
+unsigned long long V8GoCurrentThreadID();
+
+// V8GoHasExtensions reports whether the linked library exports the V8GO_EXT
+// entry points of v8_export.h.
+int V8GoHasExtensions();
+
 #ifdef __cpluscplus
 }
 #endif
\ No newline at end of file
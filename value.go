@@ -41,6 +41,9 @@ func NewValueStruct(valPtr C.ValuePtr, c *Isolate) (ret *Value) {
 }
 
 func (v *Value) MarkValuePtrCanReleaseInC() {
+	if v.ISO.isCachedValue(v) {
+		return
+	}
 	runtime.SetFinalizer(v, nil)
 	v.ISO.MoveTracedPtrToCanReleaseMap(v.ptr, true)
 }
@@ -99,6 +102,7 @@ func NewValue(iso *Isolate, val interface{}) (rtnVal *Value, e error) {
 	if iso == nil {
 		return nil, errors.New("v8go: failed to create new Value: Isolate cannot be <nil>")
 	}
+	iso.checkThread()
 	rfValue := reflect.ValueOf(val)
 	kind := rfValue.Kind()
 	if kind == reflect.Slice || kind == reflect.Array {
@@ -108,7 +112,7 @@ func NewValue(iso *Isolate, val interface{}) (rtnVal *Value, e error) {
 			arg := rfValue.Index(i).Interface()
 			value, jsValueError := NewValue(iso, arg)
 			if jsValueError != nil {
-				panic(jsValueError)
+				return nil, fmt.Errorf("v8go: unsupported element at index %d: %w", i, jsValueError)
 			}
 			valuePointers = append(valuePointers, value.ptr)
 		}
@@ -124,7 +128,11 @@ func NewValue(iso *Isolate, val interface{}) (rtnVal *Value, e error) {
 		case string:
 			cstr := C.CString(v)
 			defer FreeCPtr(unsafe.Pointer(cstr))
-			rtn := C.NewValueString(iso.ptr, cstr)
+			if !hasExtensions {
+				// NUL bytes end the string with the older entry point
+				return valueResult(iso, C.NewValueString(iso.ptr, cstr))
+			}
+			rtn := C.NewValueStringFromBytes(iso.ptr, cstr, C.int(len(v)))
 			return valueResult(iso, rtn)
 		case int8:
 			rtnVal = NewValueStruct(C.NewValueInteger(iso.ptr, C.int(int32(v))), iso)
@@ -250,7 +258,7 @@ func (v *Value) Boolean() bool {
 func (v *Value) DetailString() string {
 	rtn := C.ValueToDetailString(v.ptr)
 	if rtn.string == nil {
-		err := newJSError(rtn.error)
+		err := newJSError(v.ISO, rtn.error)
 		panic(err) // TODO: Return a fallback value
 	}
 	s := rtn.string
@@ -309,10 +317,14 @@ func (v *Value) Object() *Object {
 // are returned as-is, objects will return `[object Object]` and functions will
 // print their definition.
 func (v *Value) String() string {
-	s := C.ValueToString(v.ptr)
-	gostring := C.GoString(s)
-	FreeModuleCPtr(unsafe.Pointer(s))
-	return gostring
+	if !hasExtensions {
+		cs := C.ValueToString(v.ptr)
+		defer FreeModuleCPtr(unsafe.Pointer(cs))
+		return C.GoString(cs)
+	}
+	s := C.ValueToStringBytes(v.ptr)
+	defer FreeModuleCPtr(s.data)
+	return C.GoStringN((*C.char)(s.data), s.length)
 }
 
 // Uint32 perform the equivalent of `Number(value)` in JS and convert the result to an
@@ -327,6 +339,19 @@ func (v *Value) SameValue(other *Value) bool {
 	return C.ValueSameValue(v.ptr, other.ptr) != 0
 }
 
+// InstanceOf returns true if the value is an instance of the constructor, by
+// performing the equivalent of `v instanceof ctor` in JS in ctx.
+func (v *Value) InstanceOf(ctx *Context, ctor *Object) (bool, error) {
+	if ctx == nil {
+		return false, errors.New("v8go: Context is required")
+	}
+	if ctor == nil {
+		return false, errors.New("v8go: constructor is required")
+	}
+	v.ISO.checkThread()
+	return boolResult(v.ISO, C.ValueInstanceOf(ctx.ptr, v.ptr, ctor.ptr))
+}
+
 // IsUndefined returns true if this value is the undefined value. See ECMA-262 4.3.10.
 func (v *Value) IsUndefined() bool {
 	return C.ValueIsUndefined(v.ptr) != 0
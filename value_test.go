@@ -29,6 +29,9 @@ func TestValueNewBaseCases(t *testing.T) {
 	if _, err := v8.NewValue(iso, struct{}{}); err == nil {
 		t.Error("expected error, but got <nil>")
 	}
+	if _, err := v8.NewValue(iso, []interface{}{int32(1), struct{}{}}); err == nil {
+		t.Error("expected error for an unsupported slice element, but got <nil>")
+	}
 
 }
 
@@ -496,6 +499,37 @@ func TestValueSameValue(t *testing.T) {
 	}
 }
 
+func TestValueInstanceOf(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContextWithOptions()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, _ := ctx.RunScript("class Base {}; class Derived extends Base {}; new Derived()", "")
+	ctor := func(name string) *v8.Object {
+		v, err := ctx.RunScript(name, "")
+		fatalIf(t, err)
+		obj, _ := v.AsObject()
+		return obj
+	}
+
+	for name, expected := range map[string]bool{"Derived": true, "Base": true, "Object": true, "Array": false} {
+		ok, err := val.InstanceOf(ctx, ctor(name))
+		fatalIf(t, err)
+		if ok != expected {
+			t.Errorf("instanceof %s: expected %v, got %v", name, expected, ok)
+		}
+	}
+
+	if _, err := val.InstanceOf(ctx, ctor("({})")); err == nil {
+		t.Error("expected error for a non callable constructor")
+	}
+	if _, err := val.InstanceOf(nil, ctor("Object")); err == nil {
+		t.Error("expected error for a nil Context")
+	}
+}
+
 func TestValueIsXXX(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
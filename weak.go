@@ -0,0 +1,79 @@
+// Copyright 2021 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import "sync"
+
+// weakRef is the Go state tied to the lifetime of a JS object, released once
+// the object is garbage collected or the isolate is disposed.
+type weakRef struct {
+	iso     *Isolate
+	release func()
+}
+
+var weakMutex sync.Mutex
+var weakRegistry = make(map[int]*weakRef)
+var weakSeq = 0
+
+// setWeak calls release once obj is garbage collected, or once the isolate is
+// disposed if it is still alive then. release is called on the goroutine that
+// runs the garbage collection, which owns the isolate. The returned ref gets
+// obj back with getWeak until then.
+func (i *Isolate) setWeak(obj *Value, release func()) int {
+	weakMutex.Lock()
+	weakSeq++
+	ref := weakSeq
+	weakRegistry[ref] = &weakRef{iso: i, release: release}
+	weakMutex.Unlock()
+	C.ObjectSetWeak(obj.ptr, C.int(ref))
+	return ref
+}
+
+// getWeak returns the object of a ref returned by setWeak in ctx, or nil once
+// it was garbage collected.
+func getWeak(ctx *Context, ref int) *Object {
+	weakMutex.Lock()
+	_, ok := weakRegistry[ref]
+	weakMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	ptr := C.ObjectGetWeak(ctx.ptr, C.int(ref))
+	if ptr == nil {
+		return nil
+	}
+	return &Object{NewValueStruct(ptr, ctx.iso)}
+}
+
+//export goWeakCallback
+func goWeakCallback(ref int) {
+	weakMutex.Lock()
+	w := weakRegistry[ref]
+	delete(weakRegistry, ref)
+	weakMutex.Unlock()
+	if w != nil {
+		w.release()
+	}
+}
+
+// releaseWeakRefs releases the weak references of objects that were still
+// alive when the isolate was disposed.
+func (i *Isolate) releaseWeakRefs() {
+	weakMutex.Lock()
+	var refs []*weakRef
+	for ref, w := range weakRegistry {
+		if w.iso == i {
+			refs = append(refs, w)
+			delete(weakRegistry, ref)
+		}
+	}
+	weakMutex.Unlock()
+	for _, w := range refs {
+		w.release()
+	}
+}